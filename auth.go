@@ -0,0 +1,193 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type APIKeyLocation string
+
+const (
+	APIKeyInHeader APIKeyLocation = "header"
+	APIKeyInQuery  APIKeyLocation = "query"
+	APIKeyInCookie APIKeyLocation = "cookie"
+)
+
+const redactedSecret = "****"
+
+func redactToken(token string) string {
+	if len(token) <= 8 {
+		return redactedSecret
+	}
+	return token[:4] + "****"
+}
+
+type basicAuthMatcher struct {
+	username string
+	password string
+}
+
+func BasicAuth(username, password string) Matcher {
+	return basicAuthMatcher{username: username, password: password}
+}
+
+func (m basicAuthMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m basicAuthMatcher) String() string {
+	return fmt.Sprintf("basic auth %s:%s", m.username, redactedSecret)
+}
+
+func (m basicAuthMatcher) explain(r *http.Request) []string {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return []string{"basic auth: missing"}
+	}
+	if username != m.username {
+		return []string{fmt.Sprintf("basic auth: expected user %q, got %q", m.username, username)}
+	}
+	if password != m.password {
+		return []string{fmt.Sprintf("basic auth: expected password %s, got %s", redactedSecret, redactedSecret)}
+	}
+	return nil
+}
+
+type bearerTokenMatcher struct {
+	description string
+	match       func(string) bool
+}
+
+func BearerToken(token string) Matcher {
+	return bearerTokenMatcher{
+		description: redactToken(token),
+		match: func(value string) bool {
+			return value == token
+		},
+	}
+}
+
+func BearerTokenFunc(description string, match func(string) bool) Matcher {
+	return bearerTokenMatcher{description: description, match: match}
+}
+
+func (m bearerTokenMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m bearerTokenMatcher) String() string {
+	return fmt.Sprintf("bearer token %s", m.description)
+}
+
+func (m bearerTokenMatcher) explain(r *http.Request) []string {
+	token, ok := bearerToken(r)
+	if !ok {
+		return []string{"bearer token: missing"}
+	}
+	if !m.match(token) {
+		return []string{fmt.Sprintf("bearer token: expected %s, got %s", m.description, redactToken(token))}
+	}
+	return nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+type apiKeyMatcher struct {
+	location APIKeyLocation
+	name     string
+	value    string
+}
+
+func APIKey(location APIKeyLocation, name, value string) Matcher {
+	if location == APIKeyInHeader {
+		name = http.CanonicalHeaderKey(name)
+	}
+	return apiKeyMatcher{location: location, name: name, value: value}
+}
+
+func (m apiKeyMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m apiKeyMatcher) String() string {
+	return fmt.Sprintf("API key %s in %s: %s", m.name, m.location, redactedSecret)
+}
+
+func (m apiKeyMatcher) explain(r *http.Request) []string {
+	var value string
+	var ok bool
+	switch m.location {
+	case APIKeyInHeader:
+		var values []string
+		values, ok = r.Header[m.name]
+		if ok {
+			value = values[0]
+		}
+	case APIKeyInQuery:
+		value, ok = r.URL.Query().Get(m.name), r.URL.Query().Has(m.name)
+	case APIKeyInCookie:
+		cookie, err := r.Cookie(m.name)
+		if ok = err == nil; ok {
+			value = cookie.Value
+		}
+	default:
+		return []string{fmt.Sprintf("API key %s: unknown location %q", m.name, m.location)}
+	}
+
+	if !ok {
+		return []string{fmt.Sprintf("API key %s in %s: missing", m.name, m.location)}
+	}
+	if value != m.value {
+		return []string{fmt.Sprintf("API key %s in %s: expected %s, got %s", m.name, m.location, redactedSecret, redactedSecret)}
+	}
+	return nil
+}
+
+func redactQuery(rawQuery string, req *Request) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	names := make(map[string]bool)
+	for _, matchers := range [][]Matcher{req.queryMatchers, req.headerMatchers, req.matchers} {
+		queryAPIKeyNames(matchers, names)
+	}
+
+	redacted := false
+	for name := range names {
+		for i := range query[name] {
+			query[name][i] = redactedSecret
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return query.Encode()
+}
+
+func queryAPIKeyNames(matchers []Matcher, names map[string]bool) {
+	for _, matcher := range matchers {
+		switch m := matcher.(type) {
+		case apiKeyMatcher:
+			if m.location == APIKeyInQuery {
+				names[m.name] = true
+			}
+		case andMatcher:
+			queryAPIKeyNames(m, names)
+		case orMatcher:
+			queryAPIKeyNames(m, names)
+		case notMatcher:
+			queryAPIKeyNames([]Matcher{m.matcher}, names)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package httpmock
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func contentEncodings(r *http.Request) []string {
+	var encodings []string
+	for _, value := range r.Header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			if encoding = strings.ToLower(strings.TrimSpace(encoding)); encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	return encodings
+}
+
+func decodeBody(r *http.Request, body []byte) ([]byte, error) {
+	encodings := contentEncodings(r)
+	if len(body) == 0 || len(encodings) == 0 {
+		return body, nil
+	}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		if !decodable(encodings[i]) {
+			return body, nil
+		}
+
+		var err error
+		if body, err = decode(encodings[i], body); err != nil {
+			return nil, fmt.Errorf("could not decode %s body: %w", encodings[i], err)
+		}
+	}
+	return body, nil
+}
+
+func decodable(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "deflate":
+		return true
+	}
+	return false
+}
+
+func decode(encoding string, body []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		reader = gzipReader
+	case "deflate":
+		zlibReader, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader = flate.NewReader(bytes.NewReader(body))
+		} else {
+			reader = zlibReader
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content encoding")
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,91 @@
+package httpmock
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipBytes(data []byte) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func zlibBytes(data []byte) []byte {
+	var buffer bytes.Buffer
+	writer := zlib.NewWriter(&buffer)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func flateBytes(data []byte) []byte {
+	var buffer bytes.Buffer
+	writer, _ := flate.NewWriter(&buffer, flate.DefaultCompression)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func Test_decodeBody(t *testing.T) {
+	body := []byte(`{"name": "john"}`)
+	tests := []struct {
+		name     string
+		encoding []string
+		body     []byte
+	}{
+		{name: "no encoding", body: body},
+		{name: "identity", encoding: []string{"identity"}, body: body},
+		{name: "gzip", encoding: []string{"gzip"}, body: gzipBytes(body)},
+		{name: "x-gzip", encoding: []string{"X-Gzip"}, body: gzipBytes(body)},
+		{name: "deflate", encoding: []string{"deflate"}, body: zlibBytes(body)},
+		{name: "raw deflate", encoding: []string{"deflate"}, body: flateBytes(body)},
+		{name: "several encodings", encoding: []string{"deflate, gzip"}, body: gzipBytes(zlibBytes(body))},
+		{name: "several headers", encoding: []string{"gzip", "gzip"}, body: gzipBytes(gzipBytes(body))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "/", nil)
+			r.Header["Content-Encoding"] = tt.encoding
+
+			decoded, err := decodeBody(r, tt.body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, decoded)
+		})
+	}
+}
+
+func Test_decodeBody_errors(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+
+	r.Header.Set("Content-Encoding", "gzip")
+	_, err := decodeBody(r, []byte("not gzip"))
+	assert.EqualError(t, err, "could not decode gzip body: unexpected EOF")
+}
+
+func Test_decodeBody_unsupported(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+
+	r.Header.Set("Content-Encoding", "br")
+	decoded, err := decodeBody(r, []byte("data"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), decoded)
+
+	r.Header.Set("Content-Encoding", "gzip, zstd")
+	decoded, err = decodeBody(r, []byte("data"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), decoded)
+
+	r.Header.Set("Content-Encoding", "zstd, gzip")
+	decoded, err = decodeBody(r, gzipBytes([]byte("data")))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), decoded)
+}
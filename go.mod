@@ -1,6 +1,6 @@
 module github.com/oupo1337/httpmock
 
-go 1.20
+go 1.22
 
 require github.com/stretchr/testify v1.8.0
 
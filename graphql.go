@@ -0,0 +1,212 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    struct {
+		PersistedQuery struct {
+			SHA256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+var graphQLOperationRegexp = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+func parseGraphQLRequest(r *http.Request) (graphQLRequest, error) {
+	var request graphQLRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return request, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+		if extensions := query.Get("extensions"); extensions != "" {
+			if err := json.Unmarshal([]byte(extensions), &request.Extensions); err != nil {
+				return request, fmt.Errorf("invalid extensions: %w", err)
+			}
+		}
+	} else if err := json.Unmarshal(requestBody(r), &request); err != nil {
+		return request, fmt.Errorf("body is not a GraphQL request: %w", err)
+	}
+
+	if request.OperationName == "" {
+		if submatches := graphQLOperationRegexp.FindStringSubmatch(normalizeGraphQL(request.Query)); submatches != nil {
+			request.OperationName = submatches[1]
+		}
+	}
+	return request, nil
+}
+
+func graphQLTokens(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' && query[i] != '\r' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case strings.HasPrefix(query[i:], `"""`):
+			j := i + 3
+			for j < len(query) && !strings.HasPrefix(query[j:], `"""`) {
+				if strings.HasPrefix(query[j:], `\"""`) {
+					j += 3
+				}
+				j++
+			}
+			j = min(j+3, len(query))
+			tokens = append(tokens, query[i:j])
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(query) && query[j] != '"' && query[j] != '\n' {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(query))
+			tokens = append(tokens, query[i:j])
+			i = j
+		case strings.HasPrefix(query[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case strings.IndexByte(graphQLPunctuators, c) >= 0:
+			tokens = append(tokens, query[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(query) && strings.IndexByte(graphQLPunctuators+" \t\n\r,#\"", query[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, query[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+const graphQLPunctuators = "{}()[]:!=@$|&"
+
+func normalizeGraphQL(query string) string {
+	builder := strings.Builder{}
+	previousWord := false
+	for _, token := range graphQLTokens(query) {
+		word := token != "..." && strings.IndexByte(graphQLPunctuators, token[0]) < 0
+		if word && previousWord {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(token)
+		previousWord = word
+	}
+	return builder.String()
+}
+
+type graphQLMatcher struct {
+	description string
+	check       func(graphQLRequest) []string
+}
+
+func GraphQLOperation(operationName string) Matcher {
+	return graphQLMatcher{
+		description: fmt.Sprintf("operation %s", operationName),
+		check: func(request graphQLRequest) []string {
+			if request.OperationName != operationName {
+				return []string{fmt.Sprintf("GraphQL operation: expected %q, got %q", operationName, request.OperationName)}
+			}
+			return nil
+		},
+	}
+}
+
+func GraphQLQuery(query string) Matcher {
+	expected := normalizeGraphQL(query)
+	return graphQLMatcher{
+		description: fmt.Sprintf("query %q", expected),
+		check: func(request graphQLRequest) []string {
+			if actual := normalizeGraphQL(request.Query); actual != expected {
+				return []string{fmt.Sprintf("GraphQL query: expected %q, got %q", expected, actual)}
+			}
+			return nil
+		},
+	}
+}
+
+func GraphQLVariables(variables string) Matcher {
+	return graphQLVariablesMatcher(variables, false)
+}
+
+func GraphQLExactVariables(variables string) Matcher {
+	return graphQLVariablesMatcher(variables, true)
+}
+
+func graphQLVariablesMatcher(variables string, exact bool) Matcher {
+	description := fmt.Sprintf("variables containing %s", variables)
+	if exact {
+		description = fmt.Sprintf("variables %s", variables)
+	}
+	return graphQLMatcher{
+		description: description,
+		check: func(request graphQLRequest) []string {
+			var expected map[string]interface{}
+			if err := json.Unmarshal([]byte(variables), &expected); err != nil {
+				return []string{fmt.Sprintf("GraphQL variables: invalid expected variables: %s", err)}
+			}
+			if !exact {
+				return jsonSubsetMismatches(expected, request.Variables, "$.variables")
+			}
+			if !reflect.DeepEqual(expected, request.Variables) && (len(expected) > 0 || len(request.Variables) > 0) {
+				return []string{jsonMismatch("$.variables", expected, request.Variables)}
+			}
+			return nil
+		},
+	}
+}
+
+func GraphQLPersistedQuery(sha256Hash string) Matcher {
+	return graphQLMatcher{
+		description: fmt.Sprintf("persisted query %s", sha256Hash),
+		check: func(request graphQLRequest) []string {
+			if actual := request.Extensions.PersistedQuery.SHA256Hash; actual != sha256Hash {
+				return []string{fmt.Sprintf("GraphQL persisted query: expected %q, got %q", sha256Hash, actual)}
+			}
+			return nil
+		},
+	}
+}
+
+func (m graphQLMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m graphQLMatcher) String() string {
+	return m.description
+}
+
+func (m graphQLMatcher) explain(r *http.Request) []string {
+	request, err := parseGraphQLRequest(r)
+	if err != nil {
+		return []string{fmt.Sprintf("GraphQL: %s", err)}
+	}
+	return m.check(request)
+}
@@ -0,0 +1,76 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_normalizeGraphQL(t *testing.T) {
+	query := `
+		# fetch a user
+		query GetUser($id: ID!) {
+			user(id: $id) {
+				id,
+				name
+				... on Admin { permissions }
+			}
+		}`
+
+	assert.Equal(t, "query GetUser($id:ID!){user(id:$id){id name...on Admin{permissions}}}", normalizeGraphQL(query))
+	assert.Equal(t, normalizeGraphQL(query), normalizeGraphQL(`query GetUser($id:ID!){user(id:$id){id name ...on Admin{permissions}}}`))
+}
+
+func Test_normalizeGraphQL_strings(t *testing.T) {
+	assert.Equal(t, `{search(text:"a # b, c  d" limit:1.5){id}}`, normalizeGraphQL(`{ search(text: "a # b, c  d", limit: 1.5) { id } } # comment`))
+	assert.Equal(t, `{search(text:"say \"hi\", # there"){id}}`, normalizeGraphQL(`{ search(text: "say \"hi\", # there") { id } }`))
+	assert.Equal(t, `{search(text:"""a, # b
+  c"""){id}}`, normalizeGraphQL(`{ search(text: """a, # b
+  c""") { id } }`))
+
+	assert.NotEqual(t, normalizeGraphQL(`{ search(text: "a b") { id } }`), normalizeGraphQL(`{ search(text: "a  b") { id } }`))
+	assert.NotEqual(t, normalizeGraphQL(`{ search(text: "a,b") { id } }`), normalizeGraphQL(`{ search(text: "ab") { id } }`))
+	assert.NotEqual(t, normalizeGraphQL(`{ search(text: "a#b") { id } }`), normalizeGraphQL(`{ search(text: "a#c") { id } }`))
+}
+
+func Test_parseGraphQLRequest(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	request, err := parseGraphQLRequest(withBody(r, []byte(`{"query": "query GetUser { user { id } }", "variables": {"id": "42"}}`)))
+	assert.NoError(t, err)
+	assert.Equal(t, "GetUser", request.OperationName)
+	assert.Equal(t, map[string]interface{}{"id": "42"}, request.Variables)
+
+	query := url.Values{
+		"operationName": {"GetUser"},
+		"variables":     {`{"id": "42"}`},
+		"extensions":    {`{"persistedQuery": {"version": 1, "sha256Hash": "ecf4edb46db40b5132295c0291d62fb65d6759a9eedfa4d5d612dd5ec54a6b38"}}`},
+	}
+	r, _ = http.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	request, err = parseGraphQLRequest(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "GetUser", request.OperationName)
+	assert.Equal(t, "ecf4edb46db40b5132295c0291d62fb65d6759a9eedfa4d5d612dd5ec54a6b38", request.Extensions.PersistedQuery.SHA256Hash)
+
+	r, _ = http.NewRequest(http.MethodPost, "/graphql", nil)
+	_, err = parseGraphQLRequest(withBody(r, []byte(`not json`)))
+	assert.Error(t, err)
+}
+
+func Test_graphQLMatchers(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	body := []byte(`{"operationName": "CreateUser", "query": "mutation CreateUser($input: UserInput!) { createUser(input: $input) { id } }", "variables": {"input": {"name": "john", "email": "john@example.com"}}}`)
+
+	assert.Empty(t, explain(GraphQLOperation("CreateUser"), r, body))
+	assert.Empty(t, explain(GraphQLQuery("mutation CreateUser($input: UserInput!) {\n  createUser(input: $input) {\n    id\n  }\n}"), r, body))
+	assert.Empty(t, explain(GraphQLVariables(`{"input": {"name": "john"}}`), r, body))
+	assert.Empty(t, explain(GraphQLExactVariables(`{"input": {"name": "john", "email": "john@example.com"}}`), r, body))
+
+	assert.Equal(t, []string{`GraphQL operation: expected "GetUser", got "CreateUser"`}, explain(GraphQLOperation("GetUser"), r, body))
+	assert.Equal(t, []string{`$.variables.input.name: expected "jane", got "john"`}, explain(GraphQLVariables(`{"input": {"name": "jane"}}`), r, body))
+	assert.Len(t, explain(GraphQLExactVariables(`{"input": {"name": "john"}}`), r, body), 1)
+	assert.Equal(t, []string{`GraphQL persisted query: expected "abc", got ""`}, explain(GraphQLPersistedQuery("abc"), r, body))
+	assert.True(t, strings.HasPrefix(explain(GraphQLQuery("query GetUser { user { id } }"), r, body)[0], "GraphQL query: expected "))
+}
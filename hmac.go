@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type HMACScheme string
+
+const (
+	HMACHex    HMACScheme = "hex"
+	HMACBase64 HMACScheme = "base64"
+	HMACGitHub HMACScheme = "github"
+	HMACStripe HMACScheme = "stripe"
+)
+
+type hmacSignatureMatcher struct {
+	header string
+	secret []byte
+	scheme HMACScheme
+}
+
+func HMACSignature(header string, secret []byte, scheme HMACScheme) Matcher {
+	return hmacSignatureMatcher{header: http.CanonicalHeaderKey(header), secret: secret, scheme: scheme}
+}
+
+func (m hmacSignatureMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m hmacSignatureMatcher) signsSentBody() {}
+
+func (m hmacSignatureMatcher) String() string {
+	return fmt.Sprintf("HMAC-SHA256 signature in header %s (%s)", m.header, m.scheme)
+}
+
+func (m hmacSignatureMatcher) explain(r *http.Request) []string {
+	signature := r.Header.Get(m.header)
+	if signature == "" {
+		return []string{fmt.Sprintf("HMAC signature %s: missing", m.header)}
+	}
+
+	body := requestBody(r)
+	var expected string
+	var valid bool
+	switch m.scheme {
+	case HMACHex:
+		expected = hex.EncodeToString(hmacSHA256Sum(m.secret, string(body)))
+		valid = hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected))
+	case HMACBase64:
+		expected = base64.StdEncoding.EncodeToString(hmacSHA256Sum(m.secret, string(body)))
+		valid = hmac.Equal([]byte(signature), []byte(expected))
+	case HMACGitHub:
+		expected = "sha256=" + hex.EncodeToString(hmacSHA256Sum(m.secret, string(body)))
+		valid = hmac.Equal([]byte(signature), []byte(expected))
+	case HMACStripe:
+		var timestamp string
+		var signatures []string
+		for _, field := range strings.Split(signature, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+			switch name {
+			case "t":
+				timestamp = value
+			case "v1":
+				signatures = append(signatures, value)
+			}
+		}
+		if timestamp == "" || len(signatures) == 0 {
+			return []string{fmt.Sprintf("HMAC signature %s: expected t=<timestamp>,v1=<signature>, got %q", m.header, signature)}
+		}
+
+		expected = "t=" + timestamp + ",v1=" + hex.EncodeToString(hmacSHA256Sum(m.secret, timestamp+"."+string(body)))
+		for _, v1 := range signatures {
+			valid = valid || hmac.Equal([]byte("t="+timestamp+",v1="+v1), []byte(expected))
+		}
+	default:
+		return []string{fmt.Sprintf("HMAC signature %s: unknown scheme %q", m.header, m.scheme)}
+	}
+
+	if !valid {
+		return []string{fmt.Sprintf("HMAC signature %s: expected %s, got %s", m.header, expected, signature)}
+	}
+	return nil
+}
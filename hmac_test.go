@@ -0,0 +1,51 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACSignature(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := []byte(`{"event": "invoice.paid"}`)
+	signature := hmacSHA256Sum(secret, string(body))
+
+	tests := []struct {
+		name      string
+		scheme    HMACScheme
+		header    string
+		signature string
+	}{
+		{name: "hex", scheme: HMACHex, header: "X-Signature", signature: hex.EncodeToString(signature)},
+		{name: "base64", scheme: HMACBase64, header: "X-Shopify-Hmac-Sha256", signature: base64.StdEncoding.EncodeToString(signature)},
+		{name: "github", scheme: HMACGitHub, header: "X-Hub-Signature-256", signature: "sha256=" + hex.EncodeToString(signature)},
+		{name: "stripe", scheme: HMACStripe, header: "Stripe-Signature", signature: "t=1684144800,v1=deadbeef,v1=" + hex.EncodeToString(hmacSHA256Sum(secret, "1684144800."+string(body)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "/webhooks", nil)
+			m := HMACSignature(tt.header, secret, tt.scheme)
+			assert.Equal(t, []string{"HMAC signature " + tt.header + ": missing"}, explain(m, r, body))
+
+			r.Header.Set(tt.header, tt.signature)
+			assert.Empty(t, explain(m, r, body))
+			assert.NotEmpty(t, explain(m, r, []byte(`{"event": "invoice.voided"}`)))
+			assert.NotEmpty(t, explain(HMACSignature(tt.header, []byte("other"), tt.scheme), r, body))
+		})
+	}
+}
+
+func TestHMACSignature_explain(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/webhooks", nil)
+	r.Header.Set("X-Hub-Signature-256", "sha256=0000")
+
+	expected := "sha256=" + hex.EncodeToString(hmacSHA256Sum([]byte("secret"), "body"))
+	assert.Equal(t, []string{"HMAC signature X-Hub-Signature-256: expected " + expected + ", got sha256=0000"}, explain(HMACSignature("x-hub-signature-256", []byte("secret"), HMACGitHub), r, []byte("body")))
+
+	r.Header.Set("Stripe-Signature", "v1=abc")
+	assert.Equal(t, []string{`HMAC signature Stripe-Signature: expected t=<timestamp>,v1=<signature>, got "v1=abc"`}, explain(HMACSignature("Stripe-Signature", []byte("secret"), HMACStripe), r, []byte("body")))
+}
@@ -2,6 +2,7 @@ package httpmock
 
 import (
 	"net/http"
+	"regexp"
 	"testing"
 )
 
@@ -13,11 +14,7 @@ type Client struct {
 type RequestOption func(*Request)
 
 func (c *Client) WithRequest(method, path string, options ...RequestOption) *Client {
-	req := &Request{
-		method:              method,
-		path:                path,
-		expectedTimesCalled: 1,
-	}
+	req := newRequest(method, path)
 	for _, option := range options {
 		option(req)
 	}
@@ -25,24 +22,47 @@ func (c *Client) WithRequest(method, path string, options ...RequestOption) *Cli
 	return c
 }
 
+func (c *Client) MostSpecificMatch() *Client {
+	c.transport.mostSpecific = true
+	return c
+}
+
+func (c *Client) LaterOverrides() *Client {
+	c.transport.laterOverrides = true
+	return c
+}
+
 func (c *Client) AssertExpectations() {
 	for _, req := range c.transport.requests {
 		if req.timesCalled < req.expectedTimesCalled {
-			c.transport.t.Errorf("httpmock should have more requests: expected [%s] %q x%d", req.method, req.path, req.expectedTimesCalled-req.timesCalled)
+			c.transport.t.Errorf("httpmock should have more requests: expected [%s] %q x%d", req.method, req.route(), req.expectedTimesCalled-req.timesCalled)
 		}
 	}
 }
 
 func (c *Client) On(method, path string) *Request {
-	req := &Request{
-		method:              method,
-		path:                path,
-		expectedTimesCalled: 1,
-	}
+	req := newRequest(method, path)
 	c.transport.requests = append(c.transport.requests, req)
 	return req
 }
 
+func (c *Client) OnRegexp(method string, re *regexp.Regexp) *Request {
+	req := newRequest(method, "")
+	req.pathRegexp = re
+	c.transport.requests = append(c.transport.requests, req)
+	return req
+}
+
+func (c *Client) OnGraphQL(operationName string) *Request {
+	req := c.On(http.MethodPost, "/graphql").ExpectGraphQL(operationName)
+	req.methods = []string{http.MethodGet, http.MethodPost}
+	return req
+}
+
+func (c *Client) OnJSONRPC(path, method string) *Request {
+	return c.On(http.MethodPost, path).ExpectJSONRPC(method)
+}
+
 func New(t *testing.T) *Client {
 	mockTransport := &transport{
 		t:        t,
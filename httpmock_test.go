@@ -1,9 +1,15 @@
 package httpmock
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -234,3 +240,777 @@ func Test_httpMock_return_headers(t *testing.T) {
 	assert.Equal(t, 1, mock.transport.requests[0].timesCalled)
 	assert.False(t, mockT.Failed())
 }
+
+func Test_httpMock_hosts(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodGet, "https://billing.example.com/v1/users",
+			ReturnStatus(http.StatusOK),
+			Times(2),
+		).
+		WithRequest(http.MethodGet, "/v1/users",
+			Host("identity.example.com:8443"),
+			ReturnStatus(http.StatusAccepted),
+		)
+
+	assert.Equal(t, "https", mock.transport.requests[0].scheme)
+	assert.Equal(t, "billing.example.com", mock.transport.requests[0].host)
+	assert.Equal(t, "/v1/users", mock.transport.requests[0].path)
+
+	req1, _ := http.NewRequest(http.MethodGet, "https://identity.example.com:8443/v1/users", nil)
+	response1, err := mock.Do(req1)
+	if response1 != nil && response1.Body != nil {
+		_ = response1.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response1.StatusCode)
+	assert.Equal(t, 1, mock.transport.requests[1].timesCalled)
+	assert.False(t, mockT.Failed())
+
+	req2, _ := http.NewRequest(http.MethodGet, "https://billing.example.com:443/v1/users", nil)
+	response2, err := mock.Do(req2)
+	if response2 != nil && response2.Body != nil {
+		_ = response2.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response2.StatusCode)
+	assert.Equal(t, 1, mock.transport.requests[0].timesCalled)
+	assert.False(t, mockT.Failed())
+
+	req3, _ := http.NewRequest(http.MethodGet, "http://billing.example.com/v1/users", nil)
+	response3, err := mock.Do(req3)
+	if response3 != nil && response3.Body != nil {
+		_ = response3.Body.Close()
+	}
+
+	assert.Error(t, err)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_host_only(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodGet, "https://api.example.com").Times(2).ReturnStatus(http.StatusOK)
+
+	for _, target := range []string{"https://api.example.com/", "https://api.example.com"} {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		response, err := mock.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	assert.Equal(t, "https://api.example.com/", mock.transport.requests[0].route())
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_path_template(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	users := mock.On(http.MethodGet, "/users/{id}").ReturnStatus(http.StatusOK).Times(2)
+
+	for _, id := range []string{"1", "42"} {
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
+		response, err := mock.Do(req)
+		if response != nil && response.Body != nil {
+			_ = response.Body.Close()
+		}
+		assert.NoError(t, err)
+	}
+
+	calls := users.Calls()
+	assert.Len(t, calls, 2)
+	assert.Equal(t, map[string]string{"id": "1"}, calls[0].PathValues)
+	assert.Equal(t, "42", calls[1].Request.PathValue("id"))
+	assert.False(t, mockT.Failed())
+	assert.Contains(t, users.String(), `"/users/{id}"`)
+}
+
+func Test_httpMock_regexp(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	users := mock.OnRegexp(http.MethodGet, regexp.MustCompile(`^/v(?P<version>\d+)/users$`)).
+		ReturnStatus(http.StatusOK).
+		Times(2)
+
+	for _, path := range []string{"/v1/users", "/v2/users"} {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.com"+path, nil)
+		response, err := mock.Do(req)
+		if response != nil && response.Body != nil {
+			_ = response.Body.Close()
+		}
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, "2", users.Calls()[1].PathValues["version"])
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_same_path_different_bodies(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/items",
+			ExpectJSON(`{"name": "first"}`),
+			ReturnStatus(http.StatusCreated),
+		).
+		WithRequest(http.MethodPost, "/items",
+			ExpectBody(`{"name": "second"}`),
+			ExpectJSON(`{"name": "second"}`),
+			ReturnStatus(http.StatusAccepted),
+		)
+
+	req, _ := http.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name": "second"}`))
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.False(t, mockT.Failed())
+
+	calls := mock.transport.requests[1].Calls()
+	assert.Equal(t, []byte(`{"name": "second"}`), calls[0].Body)
+	data, err := io.ReadAll(calls[0].Request.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name": "second"}`, string(data))
+}
+
+func Test_httpMock_json_subset(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/orders",
+			ExpectJSONSubset(`{"customer": {"name": "john"}, "items": [{"sku": "ABC"}]}`),
+			ReturnStatus(http.StatusCreated),
+		)
+
+	body := `{"id": "b8c4", "createdAt": "2023-05-15T10:00:00Z", "customer": {"id": 42, "name": "john"}, "items": [{"sku": "ABC", "quantity": 2}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_custom_matchers(t *testing.T) {
+	hasTenant := func(tenant string) func(*http.Request) bool {
+		return func(r *http.Request) bool {
+			data, err := io.ReadAll(r.Body)
+			return err == nil && strings.Contains(string(data), tenant)
+		}
+	}
+
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/events",
+			ExpectFunc("body mentions tenant acme", hasTenant("acme")),
+			ReturnStatus(http.StatusAccepted),
+		).
+		WithRequest(http.MethodPost, "/events",
+			ExpectFunc("body mentions tenant globex", hasTenant("globex")),
+			ReturnStatus(http.StatusCreated),
+		)
+
+	req, _ := http.NewRequest(http.MethodPost, "/events", strings.NewReader(`{"tenant": "globex"}`))
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_combinators(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodGet, "/reports").
+		Match(Or(HasHeader("Authorization"), HasHeader("X-Api-Key"))).
+		Match(Not(HasQueryParam("debug"))).
+		ReturnStatus(http.StatusOK)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reports?page=2", nil)
+	req.Header.Set("X-Api-Key", "key")
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_form(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/oauth/token",
+			ExpectFormValue("grant_type", "client_credentials"),
+			ExpectFormValue("client_id", "my-client"),
+			ReturnStatus(http.StatusOK),
+		)
+
+	form := url.Values{"client_secret": {"secret"}, "client_id": {"my-client"}, "grant_type": {"client_credentials"}}
+	req, _ := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_multipart(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/upload",
+			ExpectMultipartField("title", "holidays"),
+			ExpectMultipartFile("photo", "beach.png", "image/png", []byte("PNG DATA")),
+			ReturnStatus(http.StatusCreated),
+		)
+
+	req, body := newMultipartRequest(t)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_headers(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodGet, "https://cdn.example.com/assets/logo.png",
+			ExpectHeader("accept", []string{"image/webp", "image/png"}),
+			ExpectHeaderAbsent("Authorization"),
+			ExpectHeaderContains("user-agent", "my-service"),
+			ReturnStatus(http.StatusOK),
+		)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://cdn.example.com/assets/logo.png", nil)
+	req.Header.Add("Accept", "image/png")
+	req.Header.Add("Accept", "image/webp")
+	req.Header.Set("User-Agent", "my-service/1.0")
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_cookies(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "https://app.example.com/login",
+			ReturnCookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"}),
+			ReturnStatus(http.StatusNoContent),
+		).
+		WithRequest(http.MethodGet, "https://app.example.com/profile",
+			ExpectCookie("session", "abc"),
+			ReturnStatus(http.StatusOK),
+		)
+	jar, _ := cookiejar.New(nil)
+	mock.Jar = jar
+
+	req1, _ := http.NewRequest(http.MethodPost, "https://app.example.com/login", nil)
+	req2, _ := http.NewRequest(http.MethodGet, "https://app.example.com/profile", nil)
+	for _, req := range []*http.Request{req1, req2} {
+		response, err := mock.Do(req)
+		if response != nil && response.Body != nil {
+			_ = response.Body.Close()
+		}
+		assert.NoError(t, err)
+	}
+
+	u, _ := url.Parse("https://app.example.com/")
+	assert.Equal(t, []*http.Cookie{{Name: "session", Value: "abc"}}, jar.Cookies(u))
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_auth(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/oauth/token",
+			ExpectBasicAuth("client", "client-secret"),
+			ReturnStatus(http.StatusOK),
+		).
+		WithRequest(http.MethodGet, "/accounts",
+			ExpectBearerToken("access-token"),
+			ExpectAPIKey(APIKeyInQuery, "key", "api-key-value"),
+			ReturnStatus(http.StatusOK),
+		)
+
+	req1, _ := http.NewRequest(http.MethodPost, "/oauth/token", nil)
+	req1.SetBasicAuth("client", "client-secret")
+	req2, _ := http.NewRequest(http.MethodGet, "/accounts?key=api-key-value", nil)
+	req2.Header.Set("Authorization", "Bearer access-token")
+	for _, req := range []*http.Request{req1, req2} {
+		response, err := mock.Do(req)
+		if response != nil && response.Body != nil {
+			_ = response.Body.Close()
+		}
+		assert.NoError(t, err)
+	}
+
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_xml(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).
+		WithRequest(http.MethodPost, "/soap",
+			ExpectXML(`<Envelope><Body><GetUser id="42"/></Body></Envelope>`),
+			ExpectXMLPath("/Envelope/Body/GetUser/@id", "42"),
+			ReturnStatus(http.StatusOK),
+		)
+
+	body := `<?xml version="1.0"?>
+<Envelope>
+	<Body>
+		<GetUser id="42"></GetUser>
+	</Body>
+</Envelope>`
+	req, _ := http.NewRequest(http.MethodPost, "/soap", strings.NewReader(body))
+	response, err := mock.Do(req)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_graphql(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnGraphQL("GetUser").
+		ExpectGraphQLVariables(`{"id": "42"}`).
+		ReturnGraphQLData(map[string]interface{}{"user": map[string]string{"name": "john"}})
+	mock.OnGraphQL("DeleteUser").
+		ReturnGraphQLErrors(GraphQLError{Message: "forbidden"})
+
+	req1, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "mutation DeleteUser { deleteUser(id: 1) }"}`))
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	data1, _ := io.ReadAll(response1.Body)
+	_ = response1.Body.Close()
+	assert.Equal(t, `{"data":null,"errors":[{"message":"forbidden"}]}`, string(data1))
+
+	req2, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"operationName": "GetUser", "query": "query GetUser($id: ID!) { user(id: $id) { name } }", "variables": {"id": "42"}}`))
+	response2, err := mock.Do(req2)
+	assert.NoError(t, err)
+	data2, _ := io.ReadAll(response2.Body)
+	_ = response2.Body.Close()
+	assert.Equal(t, http.StatusOK, response2.StatusCode)
+	assert.Equal(t, `{"data":{"user":{"name":"john"}}}`, string(data2))
+
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_graphql_get(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnGraphQL("GetUser").
+		ExpectGraphQLVariables(`{"id": "42"}`).
+		ReturnGraphQLData(map[string]interface{}{"user": map[string]string{"name": "john"}})
+
+	query := url.Values{
+		"query":     {"query GetUser($id: ID!) { user(id: $id) { name } }"},
+		"variables": {`{"id": "42"}`},
+	}
+	req1, _ := http.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	data1, _ := io.ReadAll(response1.Body)
+	_ = response1.Body.Close()
+	assert.Equal(t, http.StatusOK, response1.StatusCode)
+	assert.Equal(t, `{"data":{"user":{"name":"john"}}}`, string(data1))
+	assert.False(t, mockT.Failed())
+
+	req2, _ := http.NewRequest(http.MethodPut, "/graphql", strings.NewReader(`{"query": "query GetUser { user { name } }"}`))
+	_, err = mock.Do(req2)
+	assert.ErrorIs(t, err, UnexpectedRequestErr)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_jsonRPC(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnJSONRPC("/rpc", "eth_getBalance").
+		ExpectJSONRPCParams(`["0xabc", "latest"]`).
+		ReturnJSONRPCResult("0x10")
+	mock.OnJSONRPC("/rpc", "eth_blockNumber").
+		Times(2).
+		ReturnJSONRPCResult("0x4b7")
+	mock.OnJSONRPC("/rpc", "eth_unknown").
+		ReturnJSONRPCError(JSONRPCError{Code: -32601, Message: "method not found"})
+	mock.OnJSONRPC("/rpc", "eth_subscribe")
+
+	req1, _ := http.NewRequest(http.MethodPost, "http://node.example.com/rpc", strings.NewReader(`{"jsonrpc": "2.0", "method": "eth_blockNumber", "id": 83}`))
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	data1, _ := io.ReadAll(response1.Body)
+	_ = response1.Body.Close()
+	assert.Equal(t, http.StatusOK, response1.StatusCode)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"0x4b7","id":83}`, string(data1))
+
+	req2, _ := http.NewRequest(http.MethodPost, "http://node.example.com/rpc", strings.NewReader(`[
+		{"jsonrpc": "2.0", "method": "eth_getBalance", "params": ["0xabc", "latest"], "id": "1"},
+		{"jsonrpc": "2.0", "method": "eth_subscribe", "params": ["newHeads"]},
+		{"jsonrpc": "2.0", "method": "eth_unknown", "id": "2"},
+		{"jsonrpc": "2.0", "method": "eth_blockNumber", "id": "3"}
+	]`))
+	response2, err := mock.Do(req2)
+	assert.NoError(t, err)
+	data2, _ := io.ReadAll(response2.Body)
+	_ = response2.Body.Close()
+	assert.Equal(t, http.StatusOK, response2.StatusCode)
+	assert.JSONEq(t, `[
+		{"jsonrpc": "2.0", "result": "0x10", "id": "1"},
+		{"jsonrpc": "2.0", "error": {"code": -32601, "message": "method not found"}, "id": "2"},
+		{"jsonrpc": "2.0", "result": "0x4b7", "id": "3"}
+	]`, string(data2))
+
+	mock.AssertExpectations()
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_jsonRPC_batch_missing_expectation(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	blockNumber := mock.OnJSONRPC("/", "eth_blockNumber").ReturnJSONRPCResult("0x4b7")
+
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"jsonrpc": "2.0", "method": "eth_blockNumber", "id": 1}, {"jsonrpc": "2.0", "method": "eth_chainId", "id": 2}]`))
+	_, err := mock.Do(req)
+	assert.Error(t, err)
+	assert.True(t, mockT.Failed())
+	assert.Empty(t, blockNumber.Calls())
+
+	mockT = new(testing.T)
+	mock.transport.t = mockT
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"jsonrpc": "2.0", "method": "eth_blockNumber", "id": 1}]`))
+	_, err = mock.Do(req)
+	assert.NoError(t, err)
+	mock.AssertExpectations()
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_jsonRPC_routing(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnJSONRPC("https://node.example.com/rpc", "eth_chainId").ReturnJSONRPCResult("0x1")
+
+	req, _ := http.NewRequest(http.MethodPost, "https://node.example.com/other", strings.NewReader(`{"jsonrpc": "2.0", "method": "eth_chainId", "id": 1}`))
+	_, err := mock.Do(req)
+	assert.ErrorIs(t, err, UnexpectedRequestErr)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_jsonRPC_array_body_on_other_route(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnJSONRPC("/rpc", "x")
+	mock.On(http.MethodPost, "/items").ExpectJSON(`[{"method": "create"}]`).ReturnStatus(http.StatusCreated)
+
+	req, _ := http.NewRequest(http.MethodPost, "/items", strings.NewReader(`[{"method": "create"}]`))
+	response, err := mock.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_jsonRPC_batch_responder(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.OnJSONRPC("/rpc", "echo").
+		Times(2).
+		Respond(func(r *http.Request, w ResponseBuilder) {
+			var request struct {
+				Params []string        `json:"params"`
+				ID     json.RawMessage `json:"id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			w.BodyFromObject(map[string]interface{}{"jsonrpc": "2.0", "result": request.Params[0], "id": request.ID})
+		})
+
+	req, _ := http.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`[{"jsonrpc": "2.0", "method": "echo", "params": ["a"], "id": 1}, {"jsonrpc": "2.0", "method": "echo", "params": ["b"], "id": 2}]`))
+	response, err := mock.Do(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	assert.JSONEq(t, `[{"jsonrpc": "2.0", "result": "a", "id": 1}, {"jsonrpc": "2.0", "result": "b", "id": 2}]`, string(data))
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_compressed_body(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	request := mock.On(http.MethodPost, "/users").
+		ExpectContentEncoding("gzip").
+		ExpectJSON(`{"name": "john"}`).
+		ReturnStatus(http.StatusCreated)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(gzipBytes([]byte(`{"name": "john"}`))))
+	req.Header.Set("Content-Encoding", "gzip")
+	response, err := mock.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, []byte(`{"name": "john"}`), request.Calls()[0].Body)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_compressed_body_invalid(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodPost, "/users").ExpectJSON(`{"name": "john"}`)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name": "john"}`))
+	req.Header.Set("Content-Encoding", "gzip")
+	_, err := mock.Do(req)
+	assert.Error(t, err)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_typed_json(t *testing.T) {
+	type createUser struct {
+		Name string `json:"name"`
+	}
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	mockT := new(testing.T)
+	mock := New(mockT)
+	HandleJSON(mock, http.MethodPost, "/users", func(request createUser) (user, int) {
+		return user{ID: 42, Name: request.Name}, http.StatusCreated
+	})
+	var captured createUser
+	mock.On(http.MethodPut, "/users/42").
+		With(
+			ExpectJSONAs(func(request createUser) error {
+				if request.Name == "" {
+					return fmt.Errorf("name is empty")
+				}
+				return nil
+			}),
+			CaptureJSON(&captured),
+		).
+		ReturnStatus(http.StatusNoContent)
+
+	req1, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name": "john"}`))
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	data1, _ := io.ReadAll(response1.Body)
+	_ = response1.Body.Close()
+	assert.Equal(t, http.StatusCreated, response1.StatusCode)
+	assert.Equal(t, "application/json", response1.Header.Get("Content-Type"))
+	assert.Equal(t, `{"id":42,"name":"john"}`, string(data1))
+
+	req2, _ := http.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"name": "jane"}`))
+	response2, err := mock.Do(req2)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, response2.StatusCode)
+	assert.Equal(t, createUser{Name: "jane"}, captured)
+
+	mock.AssertExpectations()
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_most_specific_match(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT).MostSpecificMatch()
+	mock.On(http.MethodGet, "/users/{id}").Times(2).ReturnStatus(http.StatusOK)
+	mock.On(http.MethodGet, "/users/42").ExpectHeader("Accept", []string{"application/xml"}).ReturnStatus(http.StatusNotAcceptable)
+
+	req1, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	req1.Header.Set("Accept", "application/xml")
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response1.StatusCode)
+
+	req2, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	response2, err := mock.Do(req2)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response2.StatusCode)
+
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_in_order(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	login := mock.On(http.MethodPost, "/login")
+	profile := mock.On(http.MethodGet, "/profile")
+	logout := mock.On(http.MethodDelete, "/session")
+	mock.InOrder(login, profile, logout)
+
+	for _, route := range [][2]string{{http.MethodPost, "/login"}, {http.MethodGet, "/profile"}, {http.MethodDelete, "/session"}} {
+		req, _ := http.NewRequest(route[0], route[1], nil)
+		_, err := mock.Do(req)
+		assert.NoError(t, err)
+	}
+
+	mock.AssertExpectations()
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_out_of_order(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	login := mock.On(http.MethodPost, "/login")
+	logout := mock.On(http.MethodDelete, "/session")
+	mock.InOrder(login, logout)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/session", nil)
+	_, err := mock.Do(req)
+	assert.ErrorIs(t, err, UnexpectedRequestErr)
+	assert.True(t, mockT.Failed())
+	assert.Equal(t, 0, len(logout.Calls()))
+}
+
+func Test_httpMock_dynamic_responses(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	users := mock.On(http.MethodGet, "/users/{id}").
+		Times(2).
+		Respond(func(r *http.Request, w ResponseBuilder) {
+			w.BodyFromObject(map[string]string{"id": r.PathValue("id")})
+		})
+	mock.On(http.MethodPost, "/echo").
+		ReturnFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{Body: r.Body}, nil
+		})
+	mock.On(http.MethodGet, "/empty").
+		ReturnFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, nil
+		})
+
+	for _, id := range []string{"1", "2"} {
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
+		response, err := mock.Do(req)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, `{"id":"`+id+`"}`, string(data))
+	}
+	assert.Len(t, users.Calls(), 2)
+
+	req1, _ := http.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello"))
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	data1, _ := io.ReadAll(response1.Body)
+	_ = response1.Body.Close()
+	assert.Equal(t, http.StatusOK, response1.StatusCode)
+	assert.Equal(t, "hello", string(data1))
+	assert.False(t, mockT.Failed())
+
+	req2, _ := http.NewRequest(http.MethodGet, "/empty", nil)
+	_, err = mock.Do(req2)
+	assert.ErrorIs(t, err, NoResponseErr)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_sigv4_body(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodPost, "https://example.amazonaws.com/").
+		ExpectSigV4("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service").
+		ReturnStatus(http.StatusOK)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/", strings.NewReader("Param1=value1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Amz-Date", "20150830T123600Z")
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a")
+	response, err := mock.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_hmac_signature_body(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"a":1}`
+
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodPost, "/webhooks").
+		ExpectHMACSignature("X-Hub-Signature-256", secret, HMACGitHub).
+		Times(2).
+		ReturnStatus(http.StatusNoContent)
+
+	req1, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req1.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(hmacSHA256Sum(secret, body)))
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, response1.StatusCode)
+	assert.False(t, mockT.Failed())
+
+	req2, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"a":2}`))
+	req2.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(hmacSHA256Sum(secret, body)))
+	_, err = mock.Do(req2)
+	assert.ErrorIs(t, err, UnexpectedRequestErr)
+	assert.True(t, mockT.Failed())
+}
+
+func Test_httpMock_unsupported_content_encoding(t *testing.T) {
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodPost, "/upload").ReturnStatus(http.StatusAccepted)
+
+	req, _ := http.NewRequest(http.MethodPost, "/upload", strings.NewReader("\x1b\x03\x00"))
+	req.Header.Set("Content-Encoding", "br")
+	response, err := mock.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.False(t, mockT.Failed())
+}
+
+func Test_httpMock_compressed_body_signatures(t *testing.T) {
+	compressed, _ := hex.DecodeString("1f8b08000000000000ff001000efff7b22616374696f6e223a22707574227d03008a93dfd110000000")
+
+	mockT := new(testing.T)
+	mock := New(mockT)
+	mock.On(http.MethodPost, "/webhooks").
+		ExpectHMACSignature("X-Sig", []byte("webhook-secret"), HMACHex).
+		ExpectJSON(`{"action": "put"}`).
+		ReturnStatus(http.StatusNoContent)
+	mock.On(http.MethodPost, "https://example.amazonaws.com/").
+		ExpectSigV4("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service").
+		ExpectJSON(`{"action": "put"}`).
+		ReturnStatus(http.StatusOK)
+
+	req1, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(compressed))
+	req1.Header.Set("Content-Encoding", "gzip")
+	req1.Header.Set("X-Sig", "905ec8b0e1b7b1c8685d4d5a96f79fc0875009c9e6f3390a35da1adf9b3a38c7")
+	response1, err := mock.Do(req1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, response1.StatusCode)
+
+	req2, _ := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/", bytes.NewReader(compressed))
+	req2.Header.Set("Content-Encoding", "gzip")
+	req2.Header.Set("X-Amz-Date", "20150830T123600Z")
+	req2.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=content-encoding;host;x-amz-date, Signature=02d5e4813ae6f1326597a33b81be6cb6fcc82dd089077addf8a5f1b441ac40ad")
+	response2, err := mock.Do(req2)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response2.StatusCode)
+
+	assert.False(t, mockT.Failed())
+}
@@ -0,0 +1,220 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func jsonSubsetMismatches(expected, actual interface{}, path string) []string {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{jsonMismatch(path, expected, actual)}
+		}
+
+		keys := make([]string, 0, len(expectedValue))
+		for key := range expectedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var mismatches []string
+		for _, key := range keys {
+			value, ok := actualValue[key]
+			if !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s.%s: missing", path, key))
+				continue
+			}
+			mismatches = append(mismatches, jsonSubsetMismatches(expectedValue[key], value, path+"."+key)...)
+		}
+		return mismatches
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			return []string{jsonMismatch(path, expected, actual)}
+		}
+		if len(expectedValue) != len(actualValue) {
+			return []string{fmt.Sprintf("%s: expected %d elements, got %d", path, len(expectedValue), len(actualValue))}
+		}
+
+		var mismatches []string
+		for i := range expectedValue {
+			mismatches = append(mismatches, jsonSubsetMismatches(expectedValue[i], actualValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return mismatches
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		return []string{jsonMismatch(path, expected, actual)}
+	}
+	return nil
+}
+
+func jsonMismatch(path string, expected, actual interface{}) string {
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(actual)
+	return fmt.Sprintf("%s: expected %s, got %s", path, expectedJSON, actualJSON)
+}
+
+type jsonPathCheck int
+
+const (
+	jsonPathEquals jsonPathCheck = iota
+	jsonPathExists
+	jsonPathType
+	jsonPathLen
+)
+
+type jsonPathExpectation struct {
+	path     string
+	check    jsonPathCheck
+	expected interface{}
+}
+
+func (e jsonPathExpectation) String() string {
+	switch e.check {
+	case jsonPathExists:
+		return fmt.Sprintf("%s exists", e.path)
+	case jsonPathType:
+		return fmt.Sprintf("%s is of type %s", e.path, e.expected)
+	case jsonPathLen:
+		return fmt.Sprintf("%s has length %d", e.path, e.expected)
+	}
+	expectedJSON, _ := json.Marshal(e.expected)
+	return fmt.Sprintf("%s == %s", e.path, expectedJSON)
+}
+
+func (e jsonPathExpectation) mismatch(document interface{}) string {
+	value, err := lookupJSONPath(document, e.path)
+	if err != nil {
+		return fmt.Sprintf("%s: %s", e.path, err)
+	}
+
+	switch e.check {
+	case jsonPathExists:
+		return ""
+	case jsonPathType:
+		if actualType := jsonType(value); actualType != e.expected {
+			return fmt.Sprintf("%s: expected type %s, got %s", e.path, e.expected, actualType)
+		}
+		return ""
+	case jsonPathLen:
+		length := -1
+		switch v := value.(type) {
+		case []interface{}:
+			length = len(v)
+		case map[string]interface{}:
+			length = len(v)
+		case string:
+			length = len(v)
+		}
+		if length < 0 {
+			return fmt.Sprintf("%s: expected a value with a length, got %s", e.path, jsonType(value))
+		}
+		if length != e.expected {
+			return fmt.Sprintf("%s: expected length %d, got %d", e.path, e.expected, length)
+		}
+		return ""
+	}
+
+	var expected interface{}
+	expectedJSON, err := json.Marshal(e.expected)
+	if err != nil {
+		return fmt.Sprintf("%s: invalid expected value: %s", e.path, err)
+	}
+	_ = json.Unmarshal(expectedJSON, &expected)
+	if !reflect.DeepEqual(expected, value) {
+		return jsonMismatch(e.path, expected, value)
+	}
+	return ""
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func lookupJSONPath(document interface{}, path string) (interface{}, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	current := document
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an object", jsonType(current))
+			}
+			if current, ok = object[key]; !ok {
+				return nil, fmt.Errorf("missing")
+			}
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [")
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+
+			if key, err := strconv.Unquote(strings.ReplaceAll(selector, "'", `"`)); err == nil {
+				object, ok := current.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s is not an object", jsonType(current))
+				}
+				if current, ok = object[key]; !ok {
+					return nil, fmt.Errorf("missing")
+				}
+				continue
+			}
+
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector [%s]", selector)
+			}
+			array, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an array", jsonType(current))
+			}
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %s out of range (length %d)", selector, len(array))
+			}
+			current = array[index]
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest[0])
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,113 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jsonSubsetMismatches(t *testing.T) {
+	tests := []struct {
+		name               string
+		expected           string
+		actual             string
+		expectedMismatches []string
+	}{
+		{
+			name:     "equal",
+			expected: `{"a": 1}`,
+			actual:   `{"a": 1}`,
+		},
+		{
+			name:     "extra fields are ignored",
+			expected: `{"a": 1, "nested": {"b": "c"}}`,
+			actual:   `{"a": 1, "id": "generated", "nested": {"b": "c", "createdAt": "2023-01-01"}}`,
+		},
+		{
+			name:               "missing field",
+			expected:           `{"a": 1, "nested": {"b": "c"}}`,
+			actual:             `{"a": 1, "nested": {}}`,
+			expectedMismatches: []string{"$.nested.b: missing"},
+		},
+		{
+			name:               "differing fields",
+			expected:           `{"a": 1, "items": [{"sku": "ABC"}, {"sku": "DEF"}]}`,
+			actual:             `{"a": 2, "items": [{"sku": "ABC", "qty": 1}, {"sku": "XYZ"}]}`,
+			expectedMismatches: []string{"$.a: expected 1, got 2", `$.items[1].sku: expected "DEF", got "XYZ"`},
+		},
+		{
+			name:               "array length",
+			expected:           `{"items": [1, 2]}`,
+			actual:             `{"items": [1]}`,
+			expectedMismatches: []string{"$.items: expected 2 elements, got 1"},
+		},
+		{
+			name:               "type mismatch",
+			expected:           `{"a": {"b": 1}}`,
+			actual:             `{"a": "b"}`,
+			expectedMismatches: []string{`$.a: expected {"b":1}, got "b"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected, actual interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.expected), &expected))
+			assert.NoError(t, json.Unmarshal([]byte(tt.actual), &actual))
+
+			assert.Equal(t, tt.expectedMismatches, jsonSubsetMismatches(expected, actual, "$"))
+		})
+	}
+}
+
+func Test_lookupJSONPath(t *testing.T) {
+	var document interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"items": [{"sku": "ABC"}, {"sku": "DEF"}], "odd key": true}`), &document))
+
+	value, err := lookupJSONPath(document, "$.items[0].sku")
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC", value)
+
+	value, err = lookupJSONPath(document, "$.items[-1]['sku']")
+	assert.NoError(t, err)
+	assert.Equal(t, "DEF", value)
+
+	value, err = lookupJSONPath(document, `$["odd key"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	_, err = lookupJSONPath(document, "$.items[2]")
+	assert.EqualError(t, err, "index 2 out of range (length 2)")
+
+	_, err = lookupJSONPath(document, "$.missing")
+	assert.EqualError(t, err, "missing")
+
+	_, err = lookupJSONPath(document, "$.items.sku")
+	assert.EqualError(t, err, "array is not an object")
+}
+
+func Test_jsonPathExpectation_mismatch(t *testing.T) {
+	var document interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"items": [{"sku": "ABC", "quantity": 2}], "total": 10.5}`), &document))
+
+	tests := []struct {
+		name             string
+		expectation      jsonPathExpectation
+		expectedMismatch string
+	}{
+		{name: "equals", expectation: jsonPathExpectation{path: "$.items[0].sku", check: jsonPathEquals, expected: "ABC"}},
+		{name: "equals number", expectation: jsonPathExpectation{path: "$.items[0].quantity", check: jsonPathEquals, expected: 2}},
+		{name: "not equals", expectation: jsonPathExpectation{path: "$.total", check: jsonPathEquals, expected: 11}, expectedMismatch: "$.total: expected 11, got 10.5"},
+		{name: "exists", expectation: jsonPathExpectation{path: "$.items", check: jsonPathExists}},
+		{name: "does not exist", expectation: jsonPathExpectation{path: "$.customer", check: jsonPathExists}, expectedMismatch: "$.customer: missing"},
+		{name: "type", expectation: jsonPathExpectation{path: "$.items", check: jsonPathType, expected: "array"}},
+		{name: "wrong type", expectation: jsonPathExpectation{path: "$.total", check: jsonPathType, expected: "string"}, expectedMismatch: "$.total: expected type string, got number"},
+		{name: "length", expectation: jsonPathExpectation{path: "$.items", check: jsonPathLen, expected: 1}},
+		{name: "wrong length", expectation: jsonPathExpectation{path: "$.items", check: jsonPathLen, expected: 3}, expectedMismatch: "$.items: expected length 3, got 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedMismatch, tt.expectation.mismatch(document))
+		})
+	}
+}
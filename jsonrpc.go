@@ -0,0 +1,122 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type JSONRPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func parseJSONRPCRequest(body []byte) (jsonRPCRequest, error) {
+	var request jsonRPCRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return request, fmt.Errorf("body is not a JSON-RPC request: %w", err)
+	}
+	return request, nil
+}
+
+func jsonRPCBatch(r *http.Request, body []byte) ([]json.RawMessage, bool) {
+	if r.Method != http.MethodPost || !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		return nil, false
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(body, &elements); err != nil || len(elements) == 0 {
+		return nil, false
+	}
+	for _, element := range elements {
+		if request, err := parseJSONRPCRequest(element); err != nil || request.Method == "" {
+			return nil, false
+		}
+	}
+	return elements, true
+}
+
+type jsonRPCMatcher struct {
+	description string
+	check       func(jsonRPCRequest) []string
+}
+
+func JSONRPCMethod(method string) Matcher {
+	return jsonRPCMatcher{
+		description: fmt.Sprintf("method %s", method),
+		check: func(request jsonRPCRequest) []string {
+			var mismatches []string
+			if request.JSONRPC != "2.0" {
+				mismatches = append(mismatches, fmt.Sprintf("JSON-RPC version: expected %q, got %q", "2.0", request.JSONRPC))
+			}
+			if request.Method != method {
+				mismatches = append(mismatches, fmt.Sprintf("JSON-RPC method: expected %q, got %q", method, request.Method))
+			}
+			return mismatches
+		},
+	}
+}
+
+func JSONRPCParams(params string) Matcher {
+	return jsonRPCMatcher{
+		description: fmt.Sprintf("params containing %s", params),
+		check: func(request jsonRPCRequest) []string {
+			var expected, actual interface{}
+			if err := json.Unmarshal([]byte(params), &expected); err != nil {
+				return []string{fmt.Sprintf("JSON-RPC params: invalid expected params: %s", err)}
+			}
+			if len(request.Params) > 0 {
+				if err := json.Unmarshal(request.Params, &actual); err != nil {
+					return []string{fmt.Sprintf("JSON-RPC params: %s", err)}
+				}
+			}
+			return jsonSubsetMismatches(expected, actual, "$.params")
+		},
+	}
+}
+
+func (m jsonRPCMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m jsonRPCMatcher) String() string {
+	return m.description
+}
+
+func (m jsonRPCMatcher) explain(r *http.Request) []string {
+	request, err := parseJSONRPCRequest(requestBody(r))
+	if err != nil {
+		return []string{fmt.Sprintf("JSON-RPC: %s", err)}
+	}
+	return m.check(request)
+}
+
+func (r *Request) jsonRPCResponseBody(body []byte) string {
+	request, err := parseJSONRPCRequest(body)
+	if err != nil || len(request.ID) == 0 {
+		return ""
+	}
+
+	response := jsonRPCResponse{JSONRPC: "2.0", Error: r.jsonRPCError, ID: request.ID}
+	if r.jsonRPCError == nil {
+		response.Result, _ = json.Marshal(r.jsonRPCResult)
+	}
+	encoded, _ := json.Marshal(response)
+	return string(encoded)
+}
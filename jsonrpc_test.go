@@ -0,0 +1,49 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jsonRPCBatch(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+
+	elements, ok := jsonRPCBatch(r, []byte(` [{"jsonrpc": "2.0", "method": "a", "id": 1}, {"jsonrpc": "2.0", "method": "b"}]`))
+	assert.True(t, ok)
+	assert.Len(t, elements, 2)
+
+	_, ok = jsonRPCBatch(r, []byte(`{"jsonrpc": "2.0", "method": "a", "id": 1}`))
+	assert.False(t, ok)
+	_, ok = jsonRPCBatch(r, []byte(`[{"name": "john"}]`))
+	assert.False(t, ok)
+	_, ok = jsonRPCBatch(r, []byte(`[]`))
+	assert.False(t, ok)
+}
+
+func Test_jsonRPCMatchers(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+	body := []byte(`{"jsonrpc": "2.0", "method": "eth_getBalance", "params": ["0xabc", "latest"], "id": 1}`)
+
+	assert.Empty(t, explain(JSONRPCMethod("eth_getBalance"), r, body))
+	assert.Empty(t, explain(JSONRPCParams(`["0xabc", "latest"]`), r, body))
+
+	assert.Equal(t, []string{`JSON-RPC method: expected "eth_blockNumber", got "eth_getBalance"`}, explain(JSONRPCMethod("eth_blockNumber"), r, body))
+	assert.Equal(t, []string{`$.params[1]: expected "earliest", got "latest"`}, explain(JSONRPCParams(`["0xabc", "earliest"]`), r, body))
+	assert.Equal(t, []string{`JSON-RPC version: expected "2.0", got "1.0"`}, explain(JSONRPCMethod("eth_getBalance"), r, []byte(`{"jsonrpc": "1.0", "method": "eth_getBalance"}`)))
+	assert.Len(t, explain(JSONRPCMethod("eth_getBalance"), r, []byte(`not json`)), 1)
+}
+
+func TestRequest_jsonRPCResponseBody(t *testing.T) {
+	r := Request{}
+	r.ReturnJSONRPCResult(map[string]string{"balance": "0x10"})
+	assert.Equal(t, `{"jsonrpc":"2.0","result":{"balance":"0x10"},"id":"abc"}`, r.jsonRPCResponseBody([]byte(`{"jsonrpc": "2.0", "method": "m", "id": "abc"}`)))
+	assert.Equal(t, "", r.jsonRPCResponseBody([]byte(`{"jsonrpc": "2.0", "method": "m"}`)))
+
+	r.ReturnJSONRPCResult(nil)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":null,"id":null}`, r.jsonRPCResponseBody([]byte(`{"jsonrpc": "2.0", "method": "m", "id": null}`)))
+
+	r.ReturnJSONRPCError(JSONRPCError{Code: -32602, Message: "invalid params"})
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params"},"id":7}`, r.jsonRPCResponseBody([]byte(`{"jsonrpc": "2.0", "method": "m", "id": 7}`)))
+}
@@ -0,0 +1,208 @@
+package httpmock
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type jwtToken struct {
+	header       map[string]interface{}
+	claims       map[string]interface{}
+	signingInput string
+	signature    []byte
+}
+
+func parseJWT(token string) (jwtToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtToken{}, fmt.Errorf("expected 3 parts, got %d", len(parts))
+	}
+
+	parsed := jwtToken{signingInput: parts[0] + "." + parts[1]}
+	if err := decodeJWTPart(parts[0], &parsed.header); err != nil {
+		return jwtToken{}, fmt.Errorf("header: %w", err)
+	}
+	if err := decodeJWTPart(parts[1], &parsed.claims); err != nil {
+		return jwtToken{}, fmt.Errorf("claims: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return jwtToken{}, fmt.Errorf("signature: %w", err)
+	}
+	parsed.signature = signature
+	return parsed, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type jwtMatcher struct {
+	description string
+	check       func(jwtToken) error
+}
+
+func JWT(check func(claims map[string]interface{}) error) Matcher {
+	return jwtMatcher{
+		description: "claims check",
+		check: func(token jwtToken) error {
+			if err := check(token.claims); err != nil {
+				return fmt.Errorf("claims: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+func JWTIssuer(issuer string) Matcher {
+	return jwtStringClaim("iss", issuer)
+}
+
+func JWTSubject(subject string) Matcher {
+	return jwtStringClaim("sub", subject)
+}
+
+func jwtStringClaim(name, expected string) Matcher {
+	return jwtMatcher{
+		description: fmt.Sprintf("claim %s: %q", name, expected),
+		check: func(token jwtToken) error {
+			actual, ok := token.claims[name]
+			if !ok {
+				return fmt.Errorf("claim %s: missing", name)
+			}
+			if actual != expected {
+				return fmt.Errorf("claim %s: expected %q, got %v", name, expected, actual)
+			}
+			return nil
+		},
+	}
+}
+
+func JWTAudience(audience string) Matcher {
+	return jwtMatcher{
+		description: fmt.Sprintf("audience %s", audience),
+		check: func(token jwtToken) error {
+			switch aud := token.claims["aud"].(type) {
+			case string:
+				if aud == audience {
+					return nil
+				}
+			case []interface{}:
+				for _, value := range aud {
+					if value == audience {
+						return nil
+					}
+				}
+			}
+			return fmt.Errorf("claim aud: expected to contain %q, got %v", audience, token.claims["aud"])
+		},
+	}
+}
+
+func JWTExpiresWithin(d time.Duration) Matcher {
+	return jwtMatcher{
+		description: fmt.Sprintf("expiring within %s", d),
+		check: func(token jwtToken) error {
+			exp, ok := token.claims["exp"].(float64)
+			if !ok {
+				return fmt.Errorf("claim exp: missing")
+			}
+
+			now := time.Now()
+			expiresAt := time.Unix(int64(exp), 0)
+			if expiresAt.Before(now) {
+				return fmt.Errorf("claim exp: expired at %s", expiresAt.UTC().Format(time.RFC3339))
+			}
+			if expiresAt.After(now.Add(d)) {
+				return fmt.Errorf("claim exp: expected within %s, expires at %s", d, expiresAt.UTC().Format(time.RFC3339))
+			}
+			return nil
+		},
+	}
+}
+
+func JWTSignedHMAC(secret []byte) Matcher {
+	return jwtMatcher{
+		description: "signed with HMAC",
+		check: func(token jwtToken) error {
+			hashAlg, err := jwtHash(token, "HS")
+			if err != nil {
+				return err
+			}
+
+			mac := hmac.New(hashAlg.New, secret)
+			mac.Write([]byte(token.signingInput))
+			if !hmac.Equal(mac.Sum(nil), token.signature) {
+				return fmt.Errorf("signature: invalid %s signature", token.header["alg"])
+			}
+			return nil
+		},
+	}
+}
+
+func JWTSignedRSA(key *rsa.PublicKey) Matcher {
+	return jwtMatcher{
+		description: "signed with RSA",
+		check: func(token jwtToken) error {
+			hashAlg, err := jwtHash(token, "RS")
+			if err != nil {
+				return err
+			}
+
+			h := hashAlg.New()
+			h.Write([]byte(token.signingInput))
+			if err := rsa.VerifyPKCS1v15(key, hashAlg, h.Sum(nil), token.signature); err != nil {
+				return fmt.Errorf("signature: invalid %s signature", token.header["alg"])
+			}
+			return nil
+		},
+	}
+}
+
+func jwtHash(token jwtToken, family string) (crypto.Hash, error) {
+	switch token.header["alg"] {
+	case family + "256":
+		return crypto.SHA256, nil
+	case family + "384":
+		return crypto.SHA384, nil
+	case family + "512":
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("signature: expected a %s algorithm, got %v", family, token.header["alg"])
+}
+
+func (m jwtMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m jwtMatcher) String() string {
+	return fmt.Sprintf("JWT %s", m.description)
+}
+
+func (m jwtMatcher) explain(r *http.Request) []string {
+	bearer, ok := bearerToken(r)
+	if !ok {
+		return []string{"JWT: bearer token missing"}
+	}
+	token, err := parseJWT(bearer)
+	if err != nil {
+		return []string{fmt.Sprintf("JWT: malformed token: %s", err)}
+	}
+	if err := m.check(token); err != nil {
+		return []string{fmt.Sprintf("JWT %s", err)}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package httpmock
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newJWT(t *testing.T, alg string, claims map[string]interface{}, sign func(signingInput string) []byte) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	assert.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	assert.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign(signingInput))
+}
+
+func hmacSHA256(secret []byte) func(string) []byte {
+	return func(signingInput string) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		return mac.Sum(nil)
+	}
+}
+
+func newJWTRequest(token string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestJWT_claims(t *testing.T) {
+	expiresAt := time.Now().Add(5 * time.Minute)
+	claims := map[string]interface{}{
+		"iss": "https://auth.example.com",
+		"sub": "user-42",
+		"aud": []string{"billing", "identity"},
+		"exp": expiresAt.Unix(),
+	}
+	r := newJWTRequest(newJWT(t, "HS256", claims, hmacSHA256([]byte("secret"))))
+
+	assert.True(t, JWTIssuer("https://auth.example.com").Match(r))
+	assert.True(t, JWTSubject("user-42").Match(r))
+	assert.True(t, JWTAudience("billing").Match(r))
+	assert.True(t, JWTExpiresWithin(10*time.Minute).Match(r))
+	assert.True(t, JWT(func(claims map[string]interface{}) error { return nil }).Match(r))
+
+	assert.Equal(t, []string{`JWT claim sub: expected "user-1", got user-42`}, explain(JWTSubject("user-1"), r, nil))
+	assert.Equal(t, []string{`JWT claim aud: expected to contain "payments", got [billing identity]`}, explain(JWTAudience("payments"), r, nil))
+	assert.Equal(t, []string{"JWT claim exp: expected within 1m0s, expires at " + expiresAt.UTC().Format(time.RFC3339)}, explain(JWTExpiresWithin(time.Minute), r, nil))
+	assert.Equal(t, []string{"JWT claims: no scope"}, explain(JWT(func(claims map[string]interface{}) error {
+		return errors.New("no scope")
+	}), r, nil))
+}
+
+func TestJWT_signatures(t *testing.T) {
+	claims := map[string]interface{}{"sub": "user-42"}
+
+	r := newJWTRequest(newJWT(t, "HS256", claims, hmacSHA256([]byte("secret"))))
+	assert.True(t, JWTSignedHMAC([]byte("secret")).Match(r))
+	assert.Equal(t, []string{"JWT signature: invalid HS256 signature"}, explain(JWTSignedHMAC([]byte("other")), r, nil))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	r = newJWTRequest(newJWT(t, "RS256", claims, func(signingInput string) []byte {
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		assert.NoError(t, err)
+		return signature
+	}))
+	assert.True(t, JWTSignedRSA(&key.PublicKey).Match(r))
+	assert.Equal(t, []string{"JWT signature: expected a HS algorithm, got RS256"}, explain(JWTSignedHMAC([]byte("secret")), r, nil))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"JWT signature: invalid RS256 signature"}, explain(JWTSignedRSA(&otherKey.PublicKey), r, nil))
+}
+
+func TestJWT_malformed(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, []string{"JWT: bearer token missing"}, explain(JWTSubject("user"), r, nil))
+
+	r = newJWTRequest("not-a-jwt")
+	assert.Equal(t, []string{"JWT: malformed token: expected 3 parts, got 1"}, explain(JWTSubject("user"), r, nil))
+}
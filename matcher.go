@@ -0,0 +1,431 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type Matcher interface {
+	Match(r *http.Request) bool
+	String() string
+}
+
+type explainer interface {
+	explain(r *http.Request) []string
+}
+
+type funcMatcher struct {
+	description string
+	match       func(*http.Request) bool
+}
+
+func (m funcMatcher) Match(r *http.Request) bool {
+	return m.match(r)
+}
+
+func (m funcMatcher) String() string {
+	return m.description
+}
+
+func MatcherFunc(description string, match func(*http.Request) bool) Matcher {
+	return funcMatcher{description: description, match: match}
+}
+
+func withBody(r *http.Request, body []byte) *http.Request {
+	clone := r.WithContext(r.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return clone
+}
+
+type sentBodyMatcher interface {
+	signsSentBody()
+}
+
+func sentBody(r *http.Request, body []byte) []byte {
+	if r.GetBody == nil {
+		return body
+	}
+	reader, err := r.GetBody()
+	if err != nil {
+		return body
+	}
+	defer reader.Close()
+	sent, err := io.ReadAll(reader)
+	if err != nil {
+		return body
+	}
+	return sent
+}
+
+func explain(m Matcher, r *http.Request, body []byte) []string {
+	if _, ok := m.(sentBodyMatcher); ok {
+		body = sentBody(r, body)
+	}
+	if e, ok := m.(explainer); ok {
+		return e.explain(withBody(r, body))
+	}
+	if !m.Match(withBody(r, body)) {
+		return []string{fmt.Sprintf("does not match: %s", m)}
+	}
+	return nil
+}
+
+type headerMatcher struct {
+	name   string
+	values []string
+}
+
+func HasHeader(name string, values ...string) Matcher {
+	return headerMatcher{name: http.CanonicalHeaderKey(name), values: values}
+}
+
+func (m headerMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m headerMatcher) String() string {
+	if len(m.values) == 0 {
+		return fmt.Sprintf("header %s present", m.name)
+	}
+	return fmt.Sprintf("header %s: %s", m.name, m.values)
+}
+
+func (m headerMatcher) explain(r *http.Request) []string {
+	values, ok := r.Header[m.name]
+	if !ok {
+		return []string{fmt.Sprintf("header %s: missing", m.name)}
+	}
+	if len(m.values) > 0 && !sameValues(m.values, values) {
+		return []string{fmt.Sprintf("header %s: expected %s, got %s", m.name, m.values, values)}
+	}
+	return nil
+}
+
+type headerAbsentMatcher struct {
+	name string
+}
+
+func HeaderAbsent(name string) Matcher {
+	return headerAbsentMatcher{name: http.CanonicalHeaderKey(name)}
+}
+
+func (m headerAbsentMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m headerAbsentMatcher) String() string {
+	return fmt.Sprintf("header %s absent", m.name)
+}
+
+func (m headerAbsentMatcher) explain(r *http.Request) []string {
+	if values, ok := r.Header[m.name]; ok {
+		return []string{fmt.Sprintf("header %s: expected absent, got %s", m.name, values)}
+	}
+	return nil
+}
+
+type headerValueMatcher struct {
+	name        string
+	description string
+	match       func(string) bool
+}
+
+func HeaderMatchesRegexp(name string, re *regexp.Regexp) Matcher {
+	return headerValueMatcher{
+		name:        http.CanonicalHeaderKey(name),
+		description: fmt.Sprintf("matching regexp %s", re),
+		match:       re.MatchString,
+	}
+}
+
+func HeaderContains(name, substring string) Matcher {
+	return headerValueMatcher{
+		name:        http.CanonicalHeaderKey(name),
+		description: fmt.Sprintf("containing %q", substring),
+		match: func(value string) bool {
+			return strings.Contains(value, substring)
+		},
+	}
+}
+
+func (m headerValueMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m headerValueMatcher) String() string {
+	return fmt.Sprintf("header %s %s", m.name, m.description)
+}
+
+func (m headerValueMatcher) explain(r *http.Request) []string {
+	values, ok := r.Header[m.name]
+	if !ok {
+		return []string{fmt.Sprintf("header %s: missing", m.name)}
+	}
+	for _, value := range values {
+		if m.match(value) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("header %s: expected a value %s, got %s", m.name, m.description, values)}
+}
+
+type cookieMatcher struct {
+	name  string
+	value string
+}
+
+func HasCookie(name, value string) Matcher {
+	return cookieMatcher{name: name, value: value}
+}
+
+func (m cookieMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m cookieMatcher) String() string {
+	return fmt.Sprintf("cookie %s: %q", m.name, m.value)
+}
+
+func (m cookieMatcher) explain(r *http.Request) []string {
+	cookie, err := r.Cookie(m.name)
+	if err != nil {
+		return []string{fmt.Sprintf("cookie %s: missing", m.name)}
+	}
+	if cookie.Value != m.value {
+		return []string{fmt.Sprintf("cookie %s: expected %q, got %q", m.name, m.value, cookie.Value)}
+	}
+	return nil
+}
+
+type queryParamMatcher struct {
+	name   string
+	values []string
+}
+
+func HasQueryParam(name string, values ...string) Matcher {
+	return queryParamMatcher{name: name, values: values}
+}
+
+func (m queryParamMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m queryParamMatcher) String() string {
+	if len(m.values) == 0 {
+		return fmt.Sprintf("query param %s present", m.name)
+	}
+	return fmt.Sprintf("query param %s: %s", m.name, m.values)
+}
+
+func (m queryParamMatcher) explain(r *http.Request) []string {
+	values, ok := r.URL.Query()[m.name]
+	if !ok {
+		return []string{fmt.Sprintf("query param %s: missing", m.name)}
+	}
+	if len(m.values) > 0 && !reflect.DeepEqual(m.values, values) {
+		return []string{fmt.Sprintf("query param %s: expected %s, got %s", m.name, m.values, values)}
+	}
+	return nil
+}
+
+type queryParamAbsentMatcher struct {
+	name string
+}
+
+func QueryParamAbsent(name string) Matcher {
+	return queryParamAbsentMatcher{name: name}
+}
+
+func (m queryParamAbsentMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m queryParamAbsentMatcher) String() string {
+	return fmt.Sprintf("query param %s absent", m.name)
+}
+
+func (m queryParamAbsentMatcher) explain(r *http.Request) []string {
+	if values, ok := r.URL.Query()[m.name]; ok {
+		return []string{fmt.Sprintf("query param %s: expected absent, got %s", m.name, values)}
+	}
+	return nil
+}
+
+type queryParamValueMatcher struct {
+	name        string
+	description string
+	match       func(string) bool
+}
+
+func QueryParamMatchesRegexp(name string, re *regexp.Regexp) Matcher {
+	return QueryParamMatchesFunc(name, fmt.Sprintf("matching regexp %s", re), re.MatchString)
+}
+
+func QueryParamMatchesFunc(name, description string, match func(string) bool) Matcher {
+	return queryParamValueMatcher{name: name, description: description, match: match}
+}
+
+func (m queryParamValueMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m queryParamValueMatcher) String() string {
+	return fmt.Sprintf("query param %s %s", m.name, m.description)
+}
+
+func (m queryParamValueMatcher) explain(r *http.Request) []string {
+	values, ok := r.URL.Query()[m.name]
+	if !ok {
+		return []string{fmt.Sprintf("query param %s: missing", m.name)}
+	}
+	for _, value := range values {
+		if m.match(value) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("query param %s: expected a value %s, got %s", m.name, m.description, values)}
+}
+
+type bodyMatcher struct {
+	body string
+}
+
+func BodyEquals(body string) Matcher {
+	return bodyMatcher{body: body}
+}
+
+func (m bodyMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m bodyMatcher) String() string {
+	return fmt.Sprintf("body %q", m.body)
+}
+
+func (m bodyMatcher) explain(r *http.Request) []string {
+	body := requestBody(r)
+	if string(body) != m.body {
+		return []string{fmt.Sprintf("body: expected %q, got %q", m.body, body)}
+	}
+	return nil
+}
+
+type pathMatcher struct {
+	pattern string
+}
+
+func PathMatches(pattern string) Matcher {
+	return pathMatcher{pattern: pattern}
+}
+
+func (m pathMatcher) Match(r *http.Request) bool {
+	_, ok := matchPath(m.pattern, r.URL.Path)
+	return ok
+}
+
+func (m pathMatcher) String() string {
+	return fmt.Sprintf("path %q", m.pattern)
+}
+
+type andMatcher []Matcher
+
+func And(matchers ...Matcher) Matcher {
+	return andMatcher(matchers)
+}
+
+func (m andMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m andMatcher) String() string {
+	return joinMatchers(m, " AND ")
+}
+
+func (m andMatcher) explain(r *http.Request) []string {
+	body := requestBody(r)
+	var reasons []string
+	for _, matcher := range m {
+		reasons = append(reasons, explain(matcher, r, body)...)
+	}
+	return reasons
+}
+
+type orMatcher []Matcher
+
+func Or(matchers ...Matcher) Matcher {
+	return orMatcher(matchers)
+}
+
+func AnyOf(matchers ...Matcher) Matcher {
+	return Or(matchers...)
+}
+
+func (m orMatcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m orMatcher) String() string {
+	return joinMatchers(m, " OR ")
+}
+
+func (m orMatcher) explain(r *http.Request) []string {
+	body := requestBody(r)
+	reason := fmt.Sprintf("none of %s matched:", m)
+	for _, matcher := range m {
+		reasons := explain(matcher, r, body)
+		if len(reasons) == 0 {
+			return nil
+		}
+		for _, child := range reasons {
+			reason += "\n\t\t- " + strings.ReplaceAll(child, "\n", "\n\t")
+		}
+	}
+	return []string{reason}
+}
+
+type notMatcher struct {
+	matcher Matcher
+}
+
+func Not(matcher Matcher) Matcher {
+	return notMatcher{matcher: matcher}
+}
+
+func (m notMatcher) Match(r *http.Request) bool {
+	return !m.matcher.Match(r)
+}
+
+func (m notMatcher) String() string {
+	return fmt.Sprintf("NOT %s", m.matcher)
+}
+
+func (m notMatcher) explain(r *http.Request) []string {
+	if m.Match(r) {
+		return nil
+	}
+	return []string{fmt.Sprintf("expected not to match: %s", m.matcher)}
+}
+
+func joinMatchers(matchers []Matcher, separator string) string {
+	descriptions := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		descriptions = append(descriptions, matcher.String())
+	}
+	return "(" + strings.Join(descriptions, separator) + ")"
+}
+
+func requestBody(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+	body, _ := io.ReadAll(r.Body)
+	return body
+}
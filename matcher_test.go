@@ -0,0 +1,104 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcherFunc(t *testing.T) {
+	m := MatcherFunc("has a user agent", func(r *http.Request) bool {
+		return r.UserAgent() != ""
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.False(t, m.Match(r))
+	assert.Equal(t, []string{"does not match: has a user agent"}, explain(m, r, nil))
+
+	r.Header.Set("User-Agent", "httpmock")
+	assert.True(t, m.Match(r))
+	assert.Empty(t, explain(m, r, nil))
+	assert.Equal(t, "has a user agent", m.String())
+}
+
+func Test_withBody(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+	clone := withBody(r, []byte("body"))
+
+	for i := 0; i < 2; i++ {
+		body, err := clone.GetBody()
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(body)
+		assert.Equal(t, "body", string(data))
+	}
+	data, _ := io.ReadAll(clone.Body)
+	assert.Equal(t, "body", string(data))
+	assert.Nil(t, r.Body)
+}
+
+func Test_builtinMatchers(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/users/42?debug=true", nil)
+	r.Header.Set("X-Api-Key", "secret")
+
+	assert.True(t, HasHeader("x-api-key").Match(r))
+	assert.True(t, HasHeader("X-Api-Key", "secret").Match(r))
+	assert.False(t, HasHeader("X-Api-Key", "other").Match(r))
+	assert.False(t, HasHeader("Authorization").Match(r))
+	assert.True(t, HasQueryParam("debug").Match(r))
+	assert.True(t, HasQueryParam("debug", "true").Match(r))
+	assert.False(t, HasQueryParam("page").Match(r))
+	assert.True(t, PathMatches("/users/{id}").Match(r))
+	assert.False(t, PathMatches("/groups/{id}").Match(r))
+	assert.True(t, BodyEquals("hello").Match(withBody(r, []byte("hello"))))
+	assert.False(t, BodyEquals("hello").Match(withBody(r, []byte("world"))))
+}
+
+func Test_combinators(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/?debug=true", nil)
+	r.Header.Set("X-Token", "abc")
+
+	assert.True(t, Or(HasHeader("Authorization"), HasHeader("X-Token")).Match(r))
+	assert.True(t, AnyOf(HasHeader("Authorization"), HasHeader("X-Token")).Match(r))
+	assert.False(t, And(HasHeader("Authorization"), HasHeader("X-Token")).Match(r))
+	assert.False(t, Not(HasQueryParam("debug")).Match(r))
+	assert.True(t, Not(HasQueryParam("page")).Match(r))
+
+	body := []byte("payload")
+	assert.True(t, And(BodyEquals("payload"), Not(BodyEquals("other"))).Match(withBody(r, body)))
+}
+
+func Test_combinators_explain(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/?debug=true", nil)
+
+	m := And(
+		Or(HasHeader("Authorization"), HasHeader("X-Token", "abc")),
+		Not(HasQueryParam("debug")),
+	)
+
+	assert.Equal(t, "((header Authorization present OR header X-Token: [abc]) AND NOT query param debug present)", m.String())
+	assert.Equal(t, []string{
+		"none of (header Authorization present OR header X-Token: [abc]) matched:\n\t\t- header Authorization: missing\n\t\t- header X-Token: missing",
+		"expected not to match: query param debug present",
+	}, explain(m, r, nil))
+}
+
+func Test_headerMatchers(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Add("Accept", "text/html")
+	r.Header.Add("Accept", "application/json")
+	r.Header.Set("X-Request-Id", "d7b1c3a0-8f4e-4b8a-9c1d-2e6f7a8b9c0d")
+
+	assert.True(t, HasHeader("accept", "application/json", "text/html").Match(r))
+	assert.True(t, HeaderAbsent("authorization").Match(r))
+	assert.False(t, HeaderAbsent("x-request-id").Match(r))
+	assert.True(t, HeaderMatchesRegexp("x-request-id", regexp.MustCompile(`^[0-9a-f-]{36}$`)).Match(r))
+	assert.False(t, HeaderMatchesRegexp("accept", regexp.MustCompile(`^image/`)).Match(r))
+	assert.True(t, HeaderContains("Accept", "json").Match(r))
+
+	assert.Equal(t, []string{"header X-Request-Id: expected absent, got [d7b1c3a0-8f4e-4b8a-9c1d-2e6f7a8b9c0d]"}, explain(HeaderAbsent("x-request-id"), r, nil))
+	assert.Equal(t, []string{`header Accept: expected a value containing "xml", got [text/html application/json]`}, explain(HeaderContains("accept", "xml"), r, nil))
+	assert.Equal(t, []string{"header Cookie: missing"}, explain(HeaderContains("cookie", "session"), r, nil))
+}
@@ -0,0 +1,144 @@
+package httpmock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+type multipartExpectation struct {
+	name        string
+	filename    string
+	contentType string
+	content     []byte
+	sha256      string
+}
+
+func (e multipartExpectation) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("part %s", e.name))
+	if e.filename != "" {
+		builder.WriteString(fmt.Sprintf(" filename=%q", e.filename))
+	}
+	if e.contentType != "" {
+		builder.WriteString(fmt.Sprintf(" content-type=%q", e.contentType))
+	}
+	if e.content != nil {
+		builder.WriteString(fmt.Sprintf(" content=%q", e.content))
+	}
+	if e.sha256 != "" {
+		builder.WriteString(fmt.Sprintf(" sha256=%s", e.sha256))
+	}
+	return builder.String()
+}
+
+func (e multipartExpectation) mismatch(part multipartPart) string {
+	var differences []string
+	if e.filename != "" && e.filename != part.filename {
+		differences = append(differences, fmt.Sprintf("filename: expected %q, got %q", e.filename, part.filename))
+	}
+	if e.contentType != "" && e.contentType != part.contentType {
+		differences = append(differences, fmt.Sprintf("content-type: expected %q, got %q", e.contentType, part.contentType))
+	}
+	if e.content != nil && !bytes.Equal(e.content, part.content) {
+		differences = append(differences, fmt.Sprintf("content: expected %q, got %q", e.content, part.content))
+	}
+	if e.sha256 != "" && !strings.EqualFold(e.sha256, part.sha256()) {
+		differences = append(differences, fmt.Sprintf("sha256: expected %s, got %s", e.sha256, part.sha256()))
+	}
+	if len(differences) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("part %s: %s", e.name, strings.Join(differences, ", "))
+}
+
+type multipartPart struct {
+	name        string
+	filename    string
+	contentType string
+	content     []byte
+}
+
+func (p multipartPart) sha256() string {
+	sum := sha256.Sum256(p.content)
+	return hex.EncodeToString(sum[:])
+}
+
+func (p multipartPart) String() string {
+	if p.filename == "" {
+		return fmt.Sprintf("part %s: %q", p.name, p.content)
+	}
+	return fmt.Sprintf("part %s filename=%q content-type=%q size=%d sha256=%s", p.name, p.filename, p.contentType, len(p.content), p.sha256())
+}
+
+func readMultipart(r *http.Request, body []byte) ([]multipartPart, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("content type is %s", mediaType)
+	}
+
+	var parts []multipartPart
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, multipartPart{
+			name:        part.FormName(),
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			content:     content,
+		})
+	}
+}
+
+func multipartMismatches(r *http.Request, body []byte, req *Request) []string {
+	if len(req.expectedMultipart) == 0 {
+		return nil
+	}
+
+	parts, err := readMultipart(r, body)
+	if err != nil {
+		return []string{fmt.Sprintf("body is not a valid multipart body: %s", err)}
+	}
+
+	var mismatches []string
+	for _, expectation := range req.expectedMultipart {
+		mismatch := fmt.Sprintf("part %s: missing", expectation.name)
+		for _, part := range parts {
+			if part.name != expectation.name {
+				continue
+			}
+			if mismatch = expectation.mismatch(part); mismatch == "" {
+				break
+			}
+		}
+		if mismatch != "" {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	if len(mismatches) > 0 {
+		for _, part := range parts {
+			mismatches = append(mismatches, fmt.Sprintf("received %s", part))
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,60 @@
+package httpmock
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMultipartRequest(t *testing.T) (*http.Request, []byte) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	assert.NoError(t, writer.WriteField("title", "holidays"))
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="photo"; filename="beach.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte("PNG DATA"))
+	assert.NoError(t, writer.Close())
+
+	r, _ := http.NewRequest(http.MethodPost, "/upload", nil)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r, body.Bytes()
+}
+
+func Test_multipartMismatches(t *testing.T) {
+	r, body := newMultipartRequest(t)
+
+	req := newRequest(http.MethodPost, "/upload").
+		ExpectMultipartField("title", "holidays").
+		ExpectMultipartFile("photo", "beach.png", "image/png", []byte("PNG DATA")).
+		ExpectMultipartFileSHA256("photo", "beach.png", "", "6f97bba9ea42c847e116b617cbc073c5d5c8e825c9a5d531d9cd83303851e1ec")
+	assert.Empty(t, multipartMismatches(r, body, req))
+
+	req = newRequest(http.MethodPost, "/upload").
+		ExpectMultipartField("title", "work").
+		ExpectMultipartFile("photo", "beach.jpg", "image/jpeg", nil).
+		ExpectMultipartField("description", "")
+
+	assert.Equal(t, []string{
+		`part title: content: expected "work", got "holidays"`,
+		`part photo: filename: expected "beach.jpg", got "beach.png", content-type: expected "image/jpeg", got "image/png"`,
+		"part description: missing",
+		`received part title: "holidays"`,
+		`received part photo filename="beach.png" content-type="image/png" size=8 sha256=6f97bba9ea42c847e116b617cbc073c5d5c8e825c9a5d531d9cd83303851e1ec`,
+	}, multipartMismatches(r, body, req))
+}
+
+func Test_multipartMismatches_not_multipart(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/upload", nil)
+	r.Header.Set("Content-Type", "application/json")
+
+	req := newRequest(http.MethodPost, "/upload").ExpectMultipartField("title", "holidays")
+	assert.Equal(t, []string{"body is not a valid multipart body: content type is application/json"}, multipartMismatches(r, []byte("{}"), req))
+}
@@ -1,27 +1,80 @@
 package httpmock
 
 import (
+	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Request struct {
 	method              string
+	methods             []string
+	scheme              string
+	host                string
 	path                string
+	pathRegexp          *regexp.Regexp
+	urlRegexp           *regexp.Regexp
 	returnStatus        int
 	returnBody          string
 	returnError         error
 	returnHeaders       http.Header
+	graphQLData         interface{}
+	graphQLErrors       []GraphQLError
+	jsonRPC             bool
+	jsonRPCResult       interface{}
+	jsonRPCError        *JSONRPCError
 	expectedBody        string
 	expectedJSON        []byte
+	expectedJSONSubset  []byte
+	expectedJSONPaths   []jsonPathExpectation
+	expectedXML         []byte
+	expectedXMLPaths    []xmlPathExpectation
 	expectedHeaders     http.Header
+	headerMatchers      []Matcher
 	expectedQueryParams url.Values
+	queryMatchers       []Matcher
+	exactQuery          bool
+	expectedForm        url.Values
+	exactForm           bool
+	expectedMultipart   []multipartExpectation
+	graphQLMatchers     []Matcher
+	jsonRPCMatchers     []Matcher
+	matchers            []Matcher
+	captures            []func([]byte)
+	responder           func(*http.Request, []byte) (*http.Response, error)
+	priority            int
 	expectedTimesCalled int
 	timesCalled         int
+	calls               []Call
+}
+
+type Call struct {
+	Request    *http.Request
+	Body       []byte
+	PathValues map[string]string
+}
+
+func newRequest(method, path string) *Request {
+	req := &Request{
+		method:              method,
+		path:                path,
+		expectedTimesCalled: 1,
+	}
+	if u, err := url.Parse(path); err == nil && u.Host != "" {
+		req.scheme = u.Scheme
+		req.host = u.Host
+		req.path = u.Path
+		if req.path == "" {
+			req.path = "/"
+		}
+	}
+	return req
 }
 
 func Times(times int) RequestOption {
@@ -35,6 +88,61 @@ func (r *Request) Times(times int) *Request {
 	return r
 }
 
+func Priority(priority int) RequestOption {
+	return func(r *Request) {
+		r.Priority(priority)
+	}
+}
+
+func (r *Request) Priority(priority int) *Request {
+	r.priority = priority
+	return r
+}
+
+func Scheme(scheme string) RequestOption {
+	return func(r *Request) {
+		r.Scheme(scheme)
+	}
+}
+
+func (r *Request) Scheme(scheme string) *Request {
+	r.scheme = scheme
+	return r
+}
+
+func Host(host string) RequestOption {
+	return func(r *Request) {
+		r.Host(host)
+	}
+}
+
+func (r *Request) Host(host string) *Request {
+	r.host = host
+	return r
+}
+
+func PathRegexp(re *regexp.Regexp) RequestOption {
+	return func(r *Request) {
+		r.PathRegexp(re)
+	}
+}
+
+func (r *Request) PathRegexp(re *regexp.Regexp) *Request {
+	r.pathRegexp = re
+	return r
+}
+
+func URLRegexp(re *regexp.Regexp) RequestOption {
+	return func(r *Request) {
+		r.URLRegexp(re)
+	}
+}
+
+func (r *Request) URLRegexp(re *regexp.Regexp) *Request {
+	r.urlRegexp = re
+	return r
+}
+
 func ReturnStatus(status int) RequestOption {
 	return func(r *Request) {
 		r.ReturnStatus(status)
@@ -94,6 +202,56 @@ func (r *Request) ReturnHeader(name string, values []string) *Request {
 	return r
 }
 
+func ReturnCookie(cookie *http.Cookie) RequestOption {
+	return func(r *Request) {
+		r.ReturnCookie(cookie)
+	}
+}
+
+func (r *Request) ReturnCookie(cookie *http.Cookie) *Request {
+	if r.returnHeaders == nil {
+		r.returnHeaders = make(map[string][]string)
+	}
+	r.returnHeaders.Add("Set-Cookie", cookie.String())
+	return r
+}
+
+func ReturnGraphQLData(data interface{}) RequestOption {
+	return func(r *Request) {
+		r.ReturnGraphQLData(data)
+	}
+}
+
+func (r *Request) ReturnGraphQLData(data interface{}) *Request {
+	r.graphQLData = data
+	return r.returnGraphQLResponse()
+}
+
+func ReturnGraphQLErrors(errors ...GraphQLError) RequestOption {
+	return func(r *Request) {
+		r.ReturnGraphQLErrors(errors...)
+	}
+}
+
+func (r *Request) ReturnGraphQLErrors(errors ...GraphQLError) *Request {
+	r.graphQLErrors = append(r.graphQLErrors, errors...)
+	return r.returnGraphQLResponse()
+}
+
+func (r *Request) returnGraphQLResponse() *Request {
+	response := map[string]interface{}{"data": r.graphQLData}
+	if len(r.graphQLErrors) > 0 {
+		response["errors"] = r.graphQLErrors
+	}
+	if r.returnStatus == 0 {
+		r.returnStatus = http.StatusOK
+	}
+	if r.returnHeaders.Get("Content-Type") == "" {
+		r.ReturnHeader("Content-Type", []string{"application/json"})
+	}
+	return r.ReturnBodyFromObject(response)
+}
+
 func ExpectBody(expectedBody string) RequestOption {
 	return func(r *Request) {
 		r.ExpectBody(expectedBody)
@@ -116,6 +274,195 @@ func (r *Request) ExpectJSON(data string) *Request {
 	return r
 }
 
+func ExpectJSONSubset(expectedJSON string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONSubset(expectedJSON)
+	}
+}
+
+func (r *Request) ExpectJSONSubset(data string) *Request {
+	r.expectedJSONSubset = []byte(data)
+	return r
+}
+
+func ExpectJSONPath(path string, value interface{}) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONPath(path, value)
+	}
+}
+
+func (r *Request) ExpectJSONPath(path string, value interface{}) *Request {
+	r.expectedJSONPaths = append(r.expectedJSONPaths, jsonPathExpectation{path: path, check: jsonPathEquals, expected: value})
+	return r
+}
+
+func ExpectJSONPathExists(path string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONPathExists(path)
+	}
+}
+
+func (r *Request) ExpectJSONPathExists(path string) *Request {
+	r.expectedJSONPaths = append(r.expectedJSONPaths, jsonPathExpectation{path: path, check: jsonPathExists})
+	return r
+}
+
+func ExpectJSONPathType(path, jsonType string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONPathType(path, jsonType)
+	}
+}
+
+func (r *Request) ExpectJSONPathType(path, jsonType string) *Request {
+	r.expectedJSONPaths = append(r.expectedJSONPaths, jsonPathExpectation{path: path, check: jsonPathType, expected: jsonType})
+	return r
+}
+
+func ExpectJSONPathLen(path string, length int) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONPathLen(path, length)
+	}
+}
+
+func (r *Request) ExpectJSONPathLen(path string, length int) *Request {
+	r.expectedJSONPaths = append(r.expectedJSONPaths, jsonPathExpectation{path: path, check: jsonPathLen, expected: length})
+	return r
+}
+
+func ExpectXML(expectedXML string) RequestOption {
+	return func(r *Request) {
+		r.ExpectXML(expectedXML)
+	}
+}
+
+func (r *Request) ExpectXML(data string) *Request {
+	r.expectedXML = []byte(data)
+	return r
+}
+
+func ExpectXMLPath(path, value string) RequestOption {
+	return func(r *Request) {
+		r.ExpectXMLPath(path, value)
+	}
+}
+
+func (r *Request) ExpectXMLPath(path, value string) *Request {
+	r.expectedXMLPaths = append(r.expectedXMLPaths, xmlPathExpectation{path: path, value: value})
+	return r
+}
+
+func ReturnJSONRPCResult(result interface{}) RequestOption {
+	return func(r *Request) {
+		r.ReturnJSONRPCResult(result)
+	}
+}
+
+func (r *Request) ReturnJSONRPCResult(result interface{}) *Request {
+	r.jsonRPCResult = result
+	r.jsonRPCError = nil
+	return r.returnJSONRPCResponse()
+}
+
+func ReturnJSONRPCError(err JSONRPCError) RequestOption {
+	return func(r *Request) {
+		r.ReturnJSONRPCError(err)
+	}
+}
+
+func (r *Request) ReturnJSONRPCError(err JSONRPCError) *Request {
+	r.jsonRPCResult = nil
+	r.jsonRPCError = &err
+	return r.returnJSONRPCResponse()
+}
+
+func (r *Request) returnJSONRPCResponse() *Request {
+	r.jsonRPC = true
+	if r.returnStatus == 0 {
+		r.returnStatus = http.StatusOK
+	}
+	if r.returnHeaders.Get("Content-Type") == "" {
+		r.ReturnHeader("Content-Type", []string{"application/json"})
+	}
+	return r
+}
+
+func ExpectGraphQL(operationName string) RequestOption {
+	return func(r *Request) {
+		r.ExpectGraphQL(operationName)
+	}
+}
+
+func (r *Request) ExpectGraphQL(operationName string) *Request {
+	r.graphQLMatchers = append(r.graphQLMatchers, GraphQLOperation(operationName))
+	return r
+}
+
+func ExpectGraphQLQuery(query string) RequestOption {
+	return func(r *Request) {
+		r.ExpectGraphQLQuery(query)
+	}
+}
+
+func (r *Request) ExpectGraphQLQuery(query string) *Request {
+	r.graphQLMatchers = append(r.graphQLMatchers, GraphQLQuery(query))
+	return r
+}
+
+func ExpectGraphQLVariables(variables string) RequestOption {
+	return func(r *Request) {
+		r.ExpectGraphQLVariables(variables)
+	}
+}
+
+func (r *Request) ExpectGraphQLVariables(variables string) *Request {
+	r.graphQLMatchers = append(r.graphQLMatchers, GraphQLVariables(variables))
+	return r
+}
+
+func ExpectGraphQLExactVariables(variables string) RequestOption {
+	return func(r *Request) {
+		r.ExpectGraphQLExactVariables(variables)
+	}
+}
+
+func (r *Request) ExpectGraphQLExactVariables(variables string) *Request {
+	r.graphQLMatchers = append(r.graphQLMatchers, GraphQLExactVariables(variables))
+	return r
+}
+
+func ExpectGraphQLPersistedQuery(sha256Hash string) RequestOption {
+	return func(r *Request) {
+		r.ExpectGraphQLPersistedQuery(sha256Hash)
+	}
+}
+
+func (r *Request) ExpectGraphQLPersistedQuery(sha256Hash string) *Request {
+	r.graphQLMatchers = append(r.graphQLMatchers, GraphQLPersistedQuery(sha256Hash))
+	return r
+}
+
+func ExpectJSONRPC(method string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONRPC(method)
+	}
+}
+
+func (r *Request) ExpectJSONRPC(method string) *Request {
+	r.jsonRPCMatchers = append(r.jsonRPCMatchers, JSONRPCMethod(method))
+	return r
+}
+
+func ExpectJSONRPCParams(params string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJSONRPCParams(params)
+	}
+}
+
+func (r *Request) ExpectJSONRPCParams(params string) *Request {
+	r.jsonRPCMatchers = append(r.jsonRPCMatchers, JSONRPCParams(params))
+	return r
+}
+
 func ExpectHeader(name string, values []string) RequestOption {
 	return func(r *Request) {
 		r.ExpectHeader(name, values)
@@ -126,7 +473,208 @@ func (r *Request) ExpectHeader(name string, values []string) *Request {
 	if r.expectedHeaders == nil {
 		r.expectedHeaders = make(map[string][]string)
 	}
-	r.expectedHeaders[name] = values
+	r.expectedHeaders[http.CanonicalHeaderKey(name)] = values
+	return r
+}
+
+func ExpectContentEncoding(encoding string) RequestOption {
+	return func(r *Request) {
+		r.ExpectContentEncoding(encoding)
+	}
+}
+
+func (r *Request) ExpectContentEncoding(encoding string) *Request {
+	return r.ExpectHeader("Content-Encoding", []string{encoding})
+}
+
+func ExpectHeaderAbsent(name string) RequestOption {
+	return func(r *Request) {
+		r.ExpectHeaderAbsent(name)
+	}
+}
+
+func (r *Request) ExpectHeaderAbsent(name string) *Request {
+	r.headerMatchers = append(r.headerMatchers, HeaderAbsent(name))
+	return r
+}
+
+func ExpectHeaderRegexp(name string, re *regexp.Regexp) RequestOption {
+	return func(r *Request) {
+		r.ExpectHeaderRegexp(name, re)
+	}
+}
+
+func (r *Request) ExpectHeaderRegexp(name string, re *regexp.Regexp) *Request {
+	r.headerMatchers = append(r.headerMatchers, HeaderMatchesRegexp(name, re))
+	return r
+}
+
+func ExpectHeaderContains(name, substring string) RequestOption {
+	return func(r *Request) {
+		r.ExpectHeaderContains(name, substring)
+	}
+}
+
+func (r *Request) ExpectHeaderContains(name, substring string) *Request {
+	r.headerMatchers = append(r.headerMatchers, HeaderContains(name, substring))
+	return r
+}
+
+func ExpectCookie(name, value string) RequestOption {
+	return func(r *Request) {
+		r.ExpectCookie(name, value)
+	}
+}
+
+func (r *Request) ExpectCookie(name, value string) *Request {
+	r.headerMatchers = append(r.headerMatchers, HasCookie(name, value))
+	return r
+}
+
+func ExpectBasicAuth(username, password string) RequestOption {
+	return func(r *Request) {
+		r.ExpectBasicAuth(username, password)
+	}
+}
+
+func (r *Request) ExpectBasicAuth(username, password string) *Request {
+	r.headerMatchers = append(r.headerMatchers, BasicAuth(username, password))
+	return r
+}
+
+func ExpectBearerToken(token string) RequestOption {
+	return func(r *Request) {
+		r.ExpectBearerToken(token)
+	}
+}
+
+func (r *Request) ExpectBearerToken(token string) *Request {
+	r.headerMatchers = append(r.headerMatchers, BearerToken(token))
+	return r
+}
+
+func ExpectBearerTokenFunc(description string, match func(string) bool) RequestOption {
+	return func(r *Request) {
+		r.ExpectBearerTokenFunc(description, match)
+	}
+}
+
+func (r *Request) ExpectBearerTokenFunc(description string, match func(string) bool) *Request {
+	r.headerMatchers = append(r.headerMatchers, BearerTokenFunc(description, match))
+	return r
+}
+
+func ExpectAPIKey(location APIKeyLocation, name, value string) RequestOption {
+	return func(r *Request) {
+		r.ExpectAPIKey(location, name, value)
+	}
+}
+
+func (r *Request) ExpectAPIKey(location APIKeyLocation, name, value string) *Request {
+	if location == APIKeyInQuery {
+		r.queryMatchers = append(r.queryMatchers, APIKey(location, name, value))
+	} else {
+		r.headerMatchers = append(r.headerMatchers, APIKey(location, name, value))
+	}
+	return r
+}
+
+func ExpectJWT(check func(claims map[string]interface{}) error) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWT(check)
+	}
+}
+
+func (r *Request) ExpectJWT(check func(claims map[string]interface{}) error) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWT(check))
+	return r
+}
+
+func ExpectJWTIssuer(issuer string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTIssuer(issuer)
+	}
+}
+
+func (r *Request) ExpectJWTIssuer(issuer string) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTIssuer(issuer))
+	return r
+}
+
+func ExpectJWTSubject(subject string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTSubject(subject)
+	}
+}
+
+func (r *Request) ExpectJWTSubject(subject string) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTSubject(subject))
+	return r
+}
+
+func ExpectJWTAudience(audience string) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTAudience(audience)
+	}
+}
+
+func (r *Request) ExpectJWTAudience(audience string) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTAudience(audience))
+	return r
+}
+
+func ExpectJWTExpiresWithin(d time.Duration) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTExpiresWithin(d)
+	}
+}
+
+func (r *Request) ExpectJWTExpiresWithin(d time.Duration) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTExpiresWithin(d))
+	return r
+}
+
+func ExpectJWTSignedHMAC(secret []byte) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTSignedHMAC(secret)
+	}
+}
+
+func (r *Request) ExpectJWTSignedHMAC(secret []byte) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTSignedHMAC(secret))
+	return r
+}
+
+func ExpectJWTSignedRSA(key *rsa.PublicKey) RequestOption {
+	return func(r *Request) {
+		r.ExpectJWTSignedRSA(key)
+	}
+}
+
+func (r *Request) ExpectJWTSignedRSA(key *rsa.PublicKey) *Request {
+	r.headerMatchers = append(r.headerMatchers, JWTSignedRSA(key))
+	return r
+}
+
+func ExpectSigV4(accessKey, secretKey, region, service string) RequestOption {
+	return func(r *Request) {
+		r.ExpectSigV4(accessKey, secretKey, region, service)
+	}
+}
+
+func (r *Request) ExpectSigV4(accessKey, secretKey, region, service string) *Request {
+	r.headerMatchers = append(r.headerMatchers, SigV4(accessKey, secretKey, region, service))
+	return r
+}
+
+func ExpectHMACSignature(header string, secret []byte, scheme HMACScheme) RequestOption {
+	return func(r *Request) {
+		r.ExpectHMACSignature(header, secret, scheme)
+	}
+}
+
+func (r *Request) ExpectHMACSignature(header string, secret []byte, scheme HMACScheme) *Request {
+	r.headerMatchers = append(r.headerMatchers, HMACSignature(header, secret, scheme))
 	return r
 }
 
@@ -158,6 +706,158 @@ func (r *Request) ExpectQueryParam(name, value string) *Request {
 	return r
 }
 
+func ExpectNoQueryParam(name string) RequestOption {
+	return func(r *Request) {
+		r.ExpectNoQueryParam(name)
+	}
+}
+
+func (r *Request) ExpectNoQueryParam(name string) *Request {
+	r.queryMatchers = append(r.queryMatchers, QueryParamAbsent(name))
+	return r
+}
+
+func ExpectQueryParamRegexp(name string, re *regexp.Regexp) RequestOption {
+	return func(r *Request) {
+		r.ExpectQueryParamRegexp(name, re)
+	}
+}
+
+func (r *Request) ExpectQueryParamRegexp(name string, re *regexp.Regexp) *Request {
+	r.queryMatchers = append(r.queryMatchers, QueryParamMatchesRegexp(name, re))
+	return r
+}
+
+func ExpectQueryParamFunc(name, description string, match func(string) bool) RequestOption {
+	return func(r *Request) {
+		r.ExpectQueryParamFunc(name, description, match)
+	}
+}
+
+func (r *Request) ExpectQueryParamFunc(name, description string, match func(string) bool) *Request {
+	r.queryMatchers = append(r.queryMatchers, QueryParamMatchesFunc(name, description, match))
+	return r
+}
+
+func ExpectExactQuery() RequestOption {
+	return func(r *Request) {
+		r.ExpectExactQuery()
+	}
+}
+
+func (r *Request) ExpectExactQuery() *Request {
+	r.exactQuery = true
+	return r
+}
+
+func ExpectFormValue(name, value string) RequestOption {
+	return func(r *Request) {
+		r.ExpectFormValue(name, value)
+	}
+}
+
+func (r *Request) ExpectFormValue(name, value string) *Request {
+	if r.expectedForm == nil {
+		r.expectedForm = make(url.Values)
+	}
+	r.expectedForm[name] = []string{value}
+	return r
+}
+
+func ExpectFormValues(values url.Values) RequestOption {
+	return func(r *Request) {
+		r.ExpectFormValues(values)
+	}
+}
+
+func (r *Request) ExpectFormValues(values url.Values) *Request {
+	if r.expectedForm == nil {
+		r.expectedForm = make(url.Values)
+	}
+	for name, v := range values {
+		r.expectedForm[name] = v
+	}
+	return r
+}
+
+func ExpectExactForm() RequestOption {
+	return func(r *Request) {
+		r.ExpectExactForm()
+	}
+}
+
+func (r *Request) ExpectExactForm() *Request {
+	r.exactForm = true
+	return r
+}
+
+func ExpectMultipartField(name, value string) RequestOption {
+	return func(r *Request) {
+		r.ExpectMultipartField(name, value)
+	}
+}
+
+func (r *Request) ExpectMultipartField(name, value string) *Request {
+	r.expectedMultipart = append(r.expectedMultipart, multipartExpectation{name: name, content: []byte(value)})
+	return r
+}
+
+func ExpectMultipartFile(name, filename, contentType string, content []byte) RequestOption {
+	return func(r *Request) {
+		r.ExpectMultipartFile(name, filename, contentType, content)
+	}
+}
+
+func (r *Request) ExpectMultipartFile(name, filename, contentType string, content []byte) *Request {
+	r.expectedMultipart = append(r.expectedMultipart, multipartExpectation{name: name, filename: filename, contentType: contentType, content: content})
+	return r
+}
+
+func ExpectMultipartFileSHA256(name, filename, contentType, sha256 string) RequestOption {
+	return func(r *Request) {
+		r.ExpectMultipartFileSHA256(name, filename, contentType, sha256)
+	}
+}
+
+func (r *Request) ExpectMultipartFileSHA256(name, filename, contentType, sha256 string) *Request {
+	r.expectedMultipart = append(r.expectedMultipart, multipartExpectation{name: name, filename: filename, contentType: contentType, sha256: sha256})
+	return r
+}
+
+func Match(m Matcher) RequestOption {
+	return func(r *Request) {
+		r.Match(m)
+	}
+}
+
+func (r *Request) Match(m Matcher) *Request {
+	r.matchers = append(r.matchers, m)
+	return r
+}
+
+func ExpectFunc(description string, match func(*http.Request) bool) RequestOption {
+	return func(r *Request) {
+		r.ExpectFunc(description, match)
+	}
+}
+
+func (r *Request) ExpectFunc(description string, match func(*http.Request) bool) *Request {
+	return r.Match(MatcherFunc(description, match))
+}
+
+func (r *Request) With(options ...RequestOption) *Request {
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+func (r *Request) Calls() []Call {
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
 func (r *Request) ContentLength() int64 {
 	contentLengthHeader := r.returnHeaders.Get("Content-Length")
 	if len(contentLengthHeader) > 0 {
@@ -169,23 +869,146 @@ func (r *Request) ContentLength() int64 {
 	return int64(len(r.returnBody))
 }
 
+func (r *Request) acceptsMethod(method string) bool {
+	if len(r.methods) == 0 {
+		return r.method == method
+	}
+	for _, accepted := range r.methods {
+		if accepted == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Request) route() string {
+	if r.urlRegexp != nil {
+		return fmt.Sprintf("regexp(%s)", r.urlRegexp)
+	}
+
+	path := r.path
+	if r.pathRegexp != nil {
+		path = fmt.Sprintf("regexp(%s)", r.pathRegexp)
+	}
+	if r.host == "" {
+		return path
+	}
+	if r.scheme == "" {
+		return "//" + r.host + path
+	}
+	return r.scheme + "://" + r.host + path
+}
+
+func (r *Request) specificity() int {
+	score := 0
+	switch {
+	case r.urlRegexp != nil, r.pathRegexp != nil, strings.Contains(r.path, "{"):
+		score += 1
+	case r.path != "":
+		score += 2
+	}
+	if r.scheme != "" {
+		score++
+	}
+	if r.host != "" {
+		score++
+	}
+	if r.expectedBody != "" {
+		score++
+	}
+	if len(r.expectedJSON) > 0 {
+		score++
+	}
+	if len(r.expectedJSONSubset) > 0 {
+		score++
+	}
+	if len(r.expectedXML) > 0 {
+		score++
+	}
+	if r.exactQuery {
+		score++
+	}
+	if r.exactForm {
+		score++
+	}
+	score += len(r.expectedJSONPaths) + len(r.expectedXMLPaths)
+	score += len(r.expectedHeaders) + len(r.headerMatchers)
+	score += len(r.expectedQueryParams) + len(r.queryMatchers)
+	score += len(r.expectedForm) + len(r.expectedMultipart)
+	score += len(r.graphQLMatchers) + len(r.jsonRPCMatchers) + len(r.matchers)
+	return score
+}
+
 func (r *Request) String() string {
 	builder := strings.Builder{}
 
-	builder.WriteString(fmt.Sprintf("Request: [%s] %q\n", r.method, r.path))
+	builder.WriteString(fmt.Sprintf("Request: [%s] %q\n", r.method, r.route()))
 
-	if len(r.expectedHeaders) > 0 {
+	if len(r.expectedHeaders) > 0 || len(r.headerMatchers) > 0 {
 		builder.WriteString("Expected headers:\n")
 		for name, values := range r.expectedHeaders {
 			builder.WriteString(fmt.Sprintf("\t- %s: %s\n", name, values))
 		}
+		for _, matcher := range r.headerMatchers {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", matcher))
+		}
 	}
 
-	if len(r.expectedQueryParams) > 0 {
-		builder.WriteString("Expected query params:\n")
+	if len(r.expectedQueryParams) > 0 || len(r.queryMatchers) > 0 || r.exactQuery {
+		if r.exactQuery {
+			builder.WriteString("Expected exact query params:\n")
+		} else {
+			builder.WriteString("Expected query params:\n")
+		}
 		for name, values := range r.expectedQueryParams {
 			builder.WriteString(fmt.Sprintf("\t- %s: %s\n", name, values))
 		}
+		for _, matcher := range r.queryMatchers {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", matcher))
+		}
+	}
+
+	if len(r.expectedForm) > 0 || r.exactForm {
+		if r.exactForm {
+			builder.WriteString("Expected exact form values:\n")
+		} else {
+			builder.WriteString("Expected form values:\n")
+		}
+		for name, values := range r.expectedForm {
+			builder.WriteString(fmt.Sprintf("\t- %s: %s\n", name, values))
+		}
+	}
+
+	if len(r.expectedXML) > 0 {
+		builder.WriteString(fmt.Sprintf("Expected XML:\n\t%q\n", string(r.expectedXML)))
+	}
+
+	if len(r.expectedXMLPaths) > 0 {
+		builder.WriteString("Expected XML paths:\n")
+		for _, expectation := range r.expectedXMLPaths {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", expectation))
+		}
+	}
+
+	if len(r.graphQLMatchers) > 0 {
+		builder.WriteString("Expected GraphQL:\n")
+		for _, matcher := range r.graphQLMatchers {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", matcher))
+		}
+	}
+
+	if len(r.jsonRPCMatchers) > 0 {
+		builder.WriteString("Expected JSON-RPC:\n")
+		for _, matcher := range r.jsonRPCMatchers {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", matcher))
+		}
+	}
+
+	if len(r.expectedMultipart) > 0 {
+		builder.WriteString("Expected multipart parts:\n")
+		for _, expectation := range r.expectedMultipart {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", expectation))
+		}
 	}
 
 	if len(r.expectedBody) > 0 {
@@ -195,5 +1018,23 @@ func (r *Request) String() string {
 	if len(r.expectedJSON) > 0 {
 		builder.WriteString(fmt.Sprintf("Expected JSON:\n\t%q\n", string(r.expectedJSON)))
 	}
+
+	if len(r.expectedJSONSubset) > 0 {
+		builder.WriteString(fmt.Sprintf("Expected JSON subset:\n\t%q\n", string(r.expectedJSONSubset)))
+	}
+
+	if len(r.expectedJSONPaths) > 0 {
+		builder.WriteString("Expected JSON paths:\n")
+		for _, expectation := range r.expectedJSONPaths {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", expectation))
+		}
+	}
+
+	if len(r.matchers) > 0 {
+		builder.WriteString("Expected matchers:\n")
+		for _, matcher := range r.matchers {
+			builder.WriteString(fmt.Sprintf("\t- %s\n", matcher))
+		}
+	}
 	return builder.String()
 }
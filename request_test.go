@@ -1,9 +1,14 @@
 package httpmock
 
 import (
+	"crypto/rsa"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +27,62 @@ func TestTimes(t *testing.T) {
 	assert.Equal(t, 1000, r.expectedTimesCalled)
 }
 
+func TestRequest_Scheme(t *testing.T) {
+	r := Request{}
+	r.Scheme("https")
+
+	assert.Equal(t, "https", r.scheme)
+}
+
+func TestScheme(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", Scheme("https"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, "https", r.scheme)
+}
+
+func TestRequest_Host(t *testing.T) {
+	r := Request{}
+	r.Host("api.example.com:8080")
+
+	assert.Equal(t, "api.example.com:8080", r.host)
+}
+
+func TestHost(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", Host("api.example.com:8080"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, "api.example.com:8080", r.host)
+}
+
+func TestRequest_PathRegexp(t *testing.T) {
+	r := Request{}
+	r.PathRegexp(regexp.MustCompile(`^/v\d+/users$`))
+
+	assert.Equal(t, regexp.MustCompile(`^/v\d+/users$`), r.pathRegexp)
+}
+
+func TestPathRegexp(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "", PathRegexp(regexp.MustCompile(`^/v\d+/users$`)))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, regexp.MustCompile(`^/v\d+/users$`), r.pathRegexp)
+}
+
+func TestRequest_URLRegexp(t *testing.T) {
+	r := Request{}
+	r.URLRegexp(regexp.MustCompile(`^https://.+\.s3\.amazonaws\.com/`))
+
+	assert.Equal(t, regexp.MustCompile(`^https://.+\.s3\.amazonaws\.com/`), r.urlRegexp)
+}
+
+func TestURLRegexp(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "", URLRegexp(regexp.MustCompile(`^https://.+\.s3\.amazonaws\.com/`)))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, regexp.MustCompile(`^https://.+\.s3\.amazonaws\.com/`), r.urlRegexp)
+}
+
 func TestRequest_ReturnStatus(t *testing.T) {
 	r := Request{}
 	r.ReturnStatus(http.StatusTeapot)
@@ -78,6 +139,85 @@ func TestReturnHeader(t *testing.T) {
 	assert.Equal(t, http.Header{"name": {"value"}}, r.returnHeaders)
 }
 
+func TestRequest_ReturnCookie(t *testing.T) {
+	r := Request{}
+	r.ReturnCookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"})
+
+	assert.Equal(t, http.Header{"Set-Cookie": {"session=abc; Path=/"}}, r.returnHeaders)
+}
+
+func TestReturnCookie(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ReturnCookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"}))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, http.Header{"Set-Cookie": {"session=abc; Path=/"}}, r.returnHeaders)
+}
+
+func TestRequest_ReturnGraphQLData(t *testing.T) {
+	r := Request{}
+	r.ReturnGraphQLData(map[string]interface{}{"user": map[string]string{"id": "42"}})
+
+	assert.Equal(t, `{"data":{"user":{"id":"42"}}}`, r.returnBody)
+	assert.Equal(t, http.StatusOK, r.returnStatus)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, r.returnHeaders)
+}
+
+func TestReturnGraphQLData(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/graphql", ReturnGraphQLData(map[string]interface{}{"user": nil}))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, `{"data":{"user":null}}`, r.returnBody)
+}
+
+func TestRequest_ReturnGraphQLErrors(t *testing.T) {
+	r := Request{}
+	r.ReturnGraphQLData(map[string]interface{}{"user": nil}).
+		ReturnGraphQLErrors(GraphQLError{Message: "not found", Path: []interface{}{"user"}})
+
+	assert.Equal(t, `{"data":{"user":null},"errors":[{"message":"not found","path":["user"]}]}`, r.returnBody)
+}
+
+func TestReturnGraphQLErrors(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/graphql", ReturnGraphQLErrors(GraphQLError{Message: "forbidden"}))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, `{"data":null,"errors":[{"message":"forbidden"}]}`, r.returnBody)
+}
+
+func TestRequest_ReturnJSONRPCResult(t *testing.T) {
+	r := Request{}
+	r.ReturnJSONRPCResult("0x10")
+
+	assert.True(t, r.jsonRPC)
+	assert.Equal(t, "0x10", r.jsonRPCResult)
+	assert.Equal(t, http.StatusOK, r.returnStatus)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, r.returnHeaders)
+}
+
+func TestReturnJSONRPCResult(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ReturnJSONRPCResult("0x10"))
+	r := mock.transport.requests[0]
+
+	assert.True(t, r.jsonRPC)
+	assert.Equal(t, "0x10", r.jsonRPCResult)
+}
+
+func TestRequest_ReturnJSONRPCError(t *testing.T) {
+	r := Request{}
+	r.ReturnJSONRPCResult("0x10").ReturnJSONRPCError(JSONRPCError{Code: -32601, Message: "method not found"})
+
+	assert.True(t, r.jsonRPC)
+	assert.Nil(t, r.jsonRPCResult)
+	assert.Equal(t, &JSONRPCError{Code: -32601, Message: "method not found"}, r.jsonRPCError)
+}
+
+func TestReturnJSONRPCError(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ReturnJSONRPCError(JSONRPCError{Code: -32000, Message: "server error"}))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, &JSONRPCError{Code: -32000, Message: "server error"}, r.jsonRPCError)
+}
+
 func TestReturnBodyFromObject(t *testing.T) {
 	test := struct {
 		Name  string `json:"name"`
@@ -136,18 +276,351 @@ func TestExpectJSON(t *testing.T) {
 	assert.Equal(t, []byte(`{"hello":"world"}`), r.expectedJSON)
 }
 
+func TestRequest_ExpectJSONSubset(t *testing.T) {
+	r := Request{}
+	r.ExpectJSONSubset(`{"hello":"world"}`)
+
+	assert.Equal(t, []byte(`{"hello":"world"}`), r.expectedJSONSubset)
+}
+
+func TestExpectJSONSubset(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectJSONSubset(`{"hello":"world"}`))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []byte(`{"hello":"world"}`), r.expectedJSONSubset)
+}
+
+func TestRequest_ExpectJSONPath(t *testing.T) {
+	r := Request{}
+	r.ExpectJSONPath("$.items[0].sku", "ABC").
+		ExpectJSONPathExists("$.id").
+		ExpectJSONPathType("$.items", "array").
+		ExpectJSONPathLen("$.items", 2)
+
+	assert.Equal(t, []jsonPathExpectation{
+		{path: "$.items[0].sku", check: jsonPathEquals, expected: "ABC"},
+		{path: "$.id", check: jsonPathExists},
+		{path: "$.items", check: jsonPathType, expected: "array"},
+		{path: "$.items", check: jsonPathLen, expected: 2},
+	}, r.expectedJSONPaths)
+}
+
+func TestExpectJSONPath(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/",
+		ExpectJSONPath("$.items[0].sku", "ABC"),
+		ExpectJSONPathExists("$.id"),
+		ExpectJSONPathType("$.items", "array"),
+		ExpectJSONPathLen("$.items", 2),
+	)
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []jsonPathExpectation{
+		{path: "$.items[0].sku", check: jsonPathEquals, expected: "ABC"},
+		{path: "$.id", check: jsonPathExists},
+		{path: "$.items", check: jsonPathType, expected: "array"},
+		{path: "$.items", check: jsonPathLen, expected: 2},
+	}, r.expectedJSONPaths)
+}
+
+func TestRequest_ExpectXML(t *testing.T) {
+	r := Request{}
+	r.ExpectXML(`<hello>world</hello>`)
+
+	assert.Equal(t, []byte(`<hello>world</hello>`), r.expectedXML)
+}
+
+func TestExpectXML(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectXML(`<hello>world</hello>`))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []byte(`<hello>world</hello>`), r.expectedXML)
+}
+
+func TestRequest_ExpectXMLPath(t *testing.T) {
+	r := Request{}
+	r.ExpectXMLPath("/Envelope/Body/GetUser/Id", "42")
+
+	assert.Equal(t, []xmlPathExpectation{{path: "/Envelope/Body/GetUser/Id", value: "42"}}, r.expectedXMLPaths)
+}
+
+func TestExpectXMLPath(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectXMLPath("/Envelope/Body/GetUser/Id", "42"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []xmlPathExpectation{{path: "/Envelope/Body/GetUser/Id", value: "42"}}, r.expectedXMLPaths)
+}
+
+func TestRequest_ExpectGraphQL(t *testing.T) {
+	r := Request{}
+	r.ExpectGraphQL("GetUser").
+		ExpectGraphQLQuery("query GetUser { user { id } }").
+		ExpectGraphQLVariables(`{"id": "42"}`).
+		ExpectGraphQLExactVariables(`{"id": "42"}`).
+		ExpectGraphQLPersistedQuery("abc")
+
+	descriptions := make([]string, 0, len(r.graphQLMatchers))
+	for _, matcher := range r.graphQLMatchers {
+		descriptions = append(descriptions, matcher.String())
+	}
+	assert.Equal(t, []string{
+		"operation GetUser",
+		`query "query GetUser{user{id}}"`,
+		`variables containing {"id": "42"}`,
+		`variables {"id": "42"}`,
+		"persisted query abc",
+	}, descriptions)
+}
+
+func TestExpectGraphQL(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/graphql",
+		ExpectGraphQL("GetUser"),
+		ExpectGraphQLQuery("query GetUser { user { id } }"),
+		ExpectGraphQLVariables(`{"id": "42"}`),
+		ExpectGraphQLExactVariables(`{"id": "42"}`),
+		ExpectGraphQLPersistedQuery("abc"),
+	)
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.graphQLMatchers, 5)
+}
+
+func TestRequest_ExpectJSONRPC(t *testing.T) {
+	r := Request{}
+	r.ExpectJSONRPC("eth_getBalance").ExpectJSONRPCParams(`["0xabc"]`)
+
+	assert.Len(t, r.jsonRPCMatchers, 2)
+	assert.Equal(t, "method eth_getBalance", r.jsonRPCMatchers[0].String())
+	assert.Equal(t, `params containing ["0xabc"]`, r.jsonRPCMatchers[1].String())
+}
+
+func TestExpectJSONRPC(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectJSONRPC("eth_getBalance"), ExpectJSONRPCParams(`["0xabc"]`))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.jsonRPCMatchers, 2)
+}
+
 func TestRequest_ExpectHeader(t *testing.T) {
 	r := Request{}
 	r.ExpectHeader("name", []string{"value"})
 
-	assert.Equal(t, http.Header{"name": {"value"}}, r.expectedHeaders)
+	assert.Equal(t, http.Header{"Name": {"value"}}, r.expectedHeaders)
 }
 
 func TestExpectHeader(t *testing.T) {
 	mock := New(t).WithRequest(http.MethodGet, "/", ExpectHeader("name", []string{"value"}))
 	r := mock.transport.requests[0]
 
-	assert.Equal(t, http.Header{"name": {"value"}}, r.expectedHeaders)
+	assert.Equal(t, http.Header{"Name": {"value"}}, r.expectedHeaders)
+}
+
+func TestRequest_ExpectContentEncoding(t *testing.T) {
+	r := Request{}
+	r.ExpectContentEncoding("gzip")
+
+	assert.Equal(t, http.Header{"Content-Encoding": {"gzip"}}, r.expectedHeaders)
+}
+
+func TestExpectContentEncoding(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectContentEncoding("gzip"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, http.Header{"Content-Encoding": {"gzip"}}, r.expectedHeaders)
+}
+
+func TestRequest_ExpectHeaderAbsent(t *testing.T) {
+	r := Request{}
+	r.ExpectHeaderAbsent("authorization")
+
+	assert.Equal(t, []Matcher{HeaderAbsent("Authorization")}, r.headerMatchers)
+}
+
+func TestExpectHeaderAbsent(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectHeaderAbsent("authorization"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{HeaderAbsent("Authorization")}, r.headerMatchers)
+}
+
+func TestRequest_ExpectHeaderRegexp(t *testing.T) {
+	r := Request{}
+	r.ExpectHeaderRegexp("x-request-id", regexp.MustCompile(`^[0-9a-f-]{36}$`))
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "header X-Request-Id matching regexp ^[0-9a-f-]{36}$", r.headerMatchers[0].String())
+}
+
+func TestExpectHeaderRegexp(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectHeaderRegexp("x-request-id", regexp.MustCompile(`^[0-9a-f-]{36}$`)))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "header X-Request-Id matching regexp ^[0-9a-f-]{36}$", r.headerMatchers[0].String())
+}
+
+func TestRequest_ExpectHeaderContains(t *testing.T) {
+	r := Request{}
+	r.ExpectHeaderContains("accept", "json")
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, `header Accept containing "json"`, r.headerMatchers[0].String())
+}
+
+func TestExpectHeaderContains(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectHeaderContains("accept", "json"))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, `header Accept containing "json"`, r.headerMatchers[0].String())
+}
+
+func TestRequest_ExpectCookie(t *testing.T) {
+	r := Request{}
+	r.ExpectCookie("session", "abc")
+
+	assert.Equal(t, []Matcher{HasCookie("session", "abc")}, r.headerMatchers)
+}
+
+func TestExpectCookie(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectCookie("session", "abc"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{HasCookie("session", "abc")}, r.headerMatchers)
+}
+
+func TestRequest_ExpectBasicAuth(t *testing.T) {
+	r := Request{}
+	r.ExpectBasicAuth("alice", "secret")
+
+	assert.Equal(t, []Matcher{BasicAuth("alice", "secret")}, r.headerMatchers)
+}
+
+func TestExpectBasicAuth(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectBasicAuth("alice", "secret"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{BasicAuth("alice", "secret")}, r.headerMatchers)
+}
+
+func TestRequest_ExpectBearerToken(t *testing.T) {
+	r := Request{}
+	r.ExpectBearerToken("token")
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "bearer token ****", r.headerMatchers[0].String())
+}
+
+func TestExpectBearerToken(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectBearerToken("token"))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "bearer token ****", r.headerMatchers[0].String())
+}
+
+func TestRequest_ExpectBearerTokenFunc(t *testing.T) {
+	r := Request{}
+	r.ExpectBearerTokenFunc("not empty", func(token string) bool { return token != "" })
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "bearer token not empty", r.headerMatchers[0].String())
+}
+
+func TestExpectBearerTokenFunc(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectBearerTokenFunc("not empty", func(token string) bool { return token != "" }))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.headerMatchers, 1)
+	assert.Equal(t, "bearer token not empty", r.headerMatchers[0].String())
+}
+
+func TestRequest_ExpectAPIKey(t *testing.T) {
+	r := Request{}
+	r.ExpectAPIKey(APIKeyInHeader, "x-api-key", "secret").
+		ExpectAPIKey(APIKeyInQuery, "api_key", "secret")
+
+	assert.Equal(t, []Matcher{APIKey(APIKeyInHeader, "X-Api-Key", "secret")}, r.headerMatchers)
+	assert.Equal(t, []Matcher{APIKey(APIKeyInQuery, "api_key", "secret")}, r.queryMatchers)
+}
+
+func TestExpectAPIKey(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/",
+		ExpectAPIKey(APIKeyInCookie, "key", "secret"),
+		ExpectAPIKey(APIKeyInQuery, "api_key", "secret"),
+	)
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{APIKey(APIKeyInCookie, "key", "secret")}, r.headerMatchers)
+	assert.Equal(t, []Matcher{APIKey(APIKeyInQuery, "api_key", "secret")}, r.queryMatchers)
+}
+
+func TestRequest_ExpectJWT(t *testing.T) {
+	r := Request{}
+	r.ExpectJWT(func(map[string]interface{}) error { return nil }).
+		ExpectJWTIssuer("issuer").
+		ExpectJWTSubject("subject").
+		ExpectJWTAudience("audience").
+		ExpectJWTExpiresWithin(time.Hour).
+		ExpectJWTSignedHMAC([]byte("secret")).
+		ExpectJWTSignedRSA(&rsa.PublicKey{})
+
+	descriptions := make([]string, 0, len(r.headerMatchers))
+	for _, matcher := range r.headerMatchers {
+		descriptions = append(descriptions, matcher.String())
+	}
+	assert.Equal(t, []string{
+		"JWT claims check",
+		`JWT claim iss: "issuer"`,
+		`JWT claim sub: "subject"`,
+		"JWT audience audience",
+		"JWT expiring within 1h0m0s",
+		"JWT signed with HMAC",
+		"JWT signed with RSA",
+	}, descriptions)
+}
+
+func TestExpectJWT(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/",
+		ExpectJWT(func(map[string]interface{}) error { return nil }),
+		ExpectJWTIssuer("issuer"),
+		ExpectJWTSubject("subject"),
+		ExpectJWTAudience("audience"),
+		ExpectJWTExpiresWithin(time.Hour),
+		ExpectJWTSignedHMAC([]byte("secret")),
+		ExpectJWTSignedRSA(&rsa.PublicKey{}),
+	)
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.headerMatchers, 7)
+}
+
+func TestRequest_ExpectSigV4(t *testing.T) {
+	r := Request{}
+	r.ExpectSigV4("AKID", "secret", "us-east-1", "s3")
+
+	assert.Equal(t, []Matcher{SigV4("AKID", "secret", "us-east-1", "s3")}, r.headerMatchers)
+}
+
+func TestExpectSigV4(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectSigV4("AKID", "secret", "us-east-1", "s3"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{SigV4("AKID", "secret", "us-east-1", "s3")}, r.headerMatchers)
+	assert.Contains(t, r.String(), "AWS SigV4 signature by AKID for s3 in us-east-1")
+}
+
+func TestRequest_ExpectHMACSignature(t *testing.T) {
+	r := Request{}
+	r.ExpectHMACSignature("x-hub-signature-256", []byte("secret"), HMACGitHub)
+
+	assert.Equal(t, []Matcher{HMACSignature("X-Hub-Signature-256", []byte("secret"), HMACGitHub)}, r.headerMatchers)
+}
+
+func TestExpectHMACSignature(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectHMACSignature("x-hub-signature-256", []byte("secret"), HMACGitHub))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{HMACSignature("X-Hub-Signature-256", []byte("secret"), HMACGitHub)}, r.headerMatchers)
 }
 
 func TestRequest_ExpectQueryParam(t *testing.T) {
@@ -178,6 +651,188 @@ func TestExpectQueryParamValues(t *testing.T) {
 	assert.Equal(t, url.Values{"name": {"value1", "value2"}}, r.expectedQueryParams)
 }
 
+func TestRequest_ExpectNoQueryParam(t *testing.T) {
+	r := Request{}
+	r.ExpectNoQueryParam("debug")
+
+	assert.Equal(t, []Matcher{QueryParamAbsent("debug")}, r.queryMatchers)
+}
+
+func TestExpectNoQueryParam(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectNoQueryParam("debug"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []Matcher{QueryParamAbsent("debug")}, r.queryMatchers)
+}
+
+func TestRequest_ExpectQueryParamRegexp(t *testing.T) {
+	r := Request{}
+	r.ExpectQueryParamRegexp("page", regexp.MustCompile(`^\d+$`))
+
+	assert.Len(t, r.queryMatchers, 1)
+	assert.Equal(t, `query param page matching regexp ^\d+$`, r.queryMatchers[0].String())
+}
+
+func TestExpectQueryParamRegexp(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectQueryParamRegexp("page", regexp.MustCompile(`^\d+$`)))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.queryMatchers, 1)
+	assert.Equal(t, `query param page matching regexp ^\d+$`, r.queryMatchers[0].String())
+}
+
+func TestRequest_ExpectQueryParamFunc(t *testing.T) {
+	r := Request{}
+	r.ExpectQueryParamFunc("page", "not empty", func(value string) bool { return value != "" })
+
+	assert.Len(t, r.queryMatchers, 1)
+	assert.Equal(t, "query param page not empty", r.queryMatchers[0].String())
+}
+
+func TestExpectQueryParamFunc(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectQueryParamFunc("page", "not empty", func(value string) bool { return value != "" }))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.queryMatchers, 1)
+	assert.Equal(t, "query param page not empty", r.queryMatchers[0].String())
+}
+
+func TestRequest_ExpectExactQuery(t *testing.T) {
+	r := Request{}
+	r.ExpectExactQuery()
+
+	assert.True(t, r.exactQuery)
+}
+
+func TestExpectExactQuery(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectExactQuery())
+	r := mock.transport.requests[0]
+
+	assert.True(t, r.exactQuery)
+}
+
+func TestRequest_ExpectFormValue(t *testing.T) {
+	r := Request{}
+	r.ExpectFormValue("grant_type", "client_credentials")
+
+	assert.Equal(t, url.Values{"grant_type": {"client_credentials"}}, r.expectedForm)
+}
+
+func TestExpectFormValue(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectFormValue("grant_type", "client_credentials"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, url.Values{"grant_type": {"client_credentials"}}, r.expectedForm)
+}
+
+func TestRequest_ExpectFormValues(t *testing.T) {
+	r := Request{}
+	r.ExpectFormValue("grant_type", "client_credentials").
+		ExpectFormValues(url.Values{"scope": {"read", "write"}})
+
+	assert.Equal(t, url.Values{"grant_type": {"client_credentials"}, "scope": {"read", "write"}}, r.expectedForm)
+}
+
+func TestExpectFormValues(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectFormValues(url.Values{"scope": {"read", "write"}}))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, url.Values{"scope": {"read", "write"}}, r.expectedForm)
+}
+
+func TestRequest_ExpectExactForm(t *testing.T) {
+	r := Request{}
+	r.ExpectExactForm()
+
+	assert.True(t, r.exactForm)
+}
+
+func TestExpectExactForm(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectExactForm())
+	r := mock.transport.requests[0]
+
+	assert.True(t, r.exactForm)
+}
+
+func TestRequest_ExpectMultipartField(t *testing.T) {
+	r := Request{}
+	r.ExpectMultipartField("title", "holidays")
+
+	assert.Equal(t, []multipartExpectation{{name: "title", content: []byte("holidays")}}, r.expectedMultipart)
+}
+
+func TestExpectMultipartField(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectMultipartField("title", "holidays"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []multipartExpectation{{name: "title", content: []byte("holidays")}}, r.expectedMultipart)
+}
+
+func TestRequest_ExpectMultipartFile(t *testing.T) {
+	r := Request{}
+	r.ExpectMultipartFile("photo", "beach.png", "image/png", []byte("data"))
+
+	assert.Equal(t, []multipartExpectation{{name: "photo", filename: "beach.png", contentType: "image/png", content: []byte("data")}}, r.expectedMultipart)
+}
+
+func TestExpectMultipartFile(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectMultipartFile("photo", "beach.png", "image/png", []byte("data")))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []multipartExpectation{{name: "photo", filename: "beach.png", contentType: "image/png", content: []byte("data")}}, r.expectedMultipart)
+}
+
+func TestRequest_ExpectMultipartFileSHA256(t *testing.T) {
+	r := Request{}
+	r.ExpectMultipartFileSHA256("photo", "beach.png", "image/png", "abcdef")
+
+	assert.Equal(t, []multipartExpectation{{name: "photo", filename: "beach.png", contentType: "image/png", sha256: "abcdef"}}, r.expectedMultipart)
+}
+
+func TestExpectMultipartFileSHA256(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/", ExpectMultipartFileSHA256("photo", "beach.png", "image/png", "abcdef"))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, []multipartExpectation{{name: "photo", filename: "beach.png", contentType: "image/png", sha256: "abcdef"}}, r.expectedMultipart)
+}
+
+func TestRequest_Match(t *testing.T) {
+	m := MatcherFunc("always", func(*http.Request) bool { return true })
+
+	r := Request{}
+	r.Match(m)
+
+	assert.Len(t, r.matchers, 1)
+	assert.Equal(t, "always", r.matchers[0].String())
+}
+
+func TestMatch(t *testing.T) {
+	m := MatcherFunc("always", func(*http.Request) bool { return true })
+
+	mock := New(t).WithRequest(http.MethodGet, "/", Match(m))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.matchers, 1)
+	assert.Equal(t, "always", r.matchers[0].String())
+}
+
+func TestRequest_ExpectFunc(t *testing.T) {
+	r := Request{}
+	r.ExpectFunc("always", func(*http.Request) bool { return true })
+
+	assert.Len(t, r.matchers, 1)
+	assert.Equal(t, "always", r.matchers[0].String())
+}
+
+func TestExpectFunc(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ExpectFunc("always", func(*http.Request) bool { return true }))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.matchers, 1)
+	assert.Equal(t, "always", r.matchers[0].String())
+	assert.Contains(t, r.String(), "Expected matchers:\n\t- always\n")
+}
+
 func TestRequest_ContentLength(t *testing.T) {
 	r := Request{}
 	assert.Equal(t, int64(0), r.ContentLength())
@@ -189,3 +844,106 @@ func TestRequest_ContentLength(t *testing.T) {
 	r.returnHeaders["Content-Length"] = []string{"1000"}
 	assert.Equal(t, int64(1000), r.ContentLength())
 }
+
+func TestRequest_With(t *testing.T) {
+	r := Request{}
+	r.With(ExpectQueryParam("page", "1"), ReturnStatus(http.StatusOK))
+
+	assert.Equal(t, url.Values{"page": {"1"}}, r.expectedQueryParams)
+	assert.Equal(t, http.StatusOK, r.returnStatus)
+}
+
+func TestExpectJSONAs(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodPost, "/users", ExpectJSONAs(func(user typedUser) error { return nil }))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.matchers, 1)
+	assert.Equal(t, "JSON body as httpmock.typedUser", r.matchers[0].String())
+}
+
+func TestCaptureJSON(t *testing.T) {
+	var user typedUser
+	mock := New(t).WithRequest(http.MethodPost, "/users", CaptureJSON(&user))
+	r := mock.transport.requests[0]
+
+	assert.Len(t, r.matchers, 1)
+	assert.Len(t, r.captures, 1)
+
+	r.captures[0]([]byte(`{"name": "john", "age": 42}`))
+	assert.Equal(t, typedUser{Name: "john", Age: 42}, user)
+}
+
+func TestRequest_Priority(t *testing.T) {
+	r := Request{}
+	r.Priority(10)
+
+	assert.Equal(t, 10, r.priority)
+}
+
+func TestPriority(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", Priority(-1))
+	r := mock.transport.requests[0]
+
+	assert.Equal(t, -1, r.priority)
+}
+
+func TestRequest_ReturnFunc(t *testing.T) {
+	r := Request{}
+	r.ReturnFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusAccepted}, nil
+	})
+
+	response, err := r.responder(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+}
+
+func TestReturnFunc(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", ReturnFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection reset")
+	}))
+	r := mock.transport.requests[0]
+
+	_, err := r.responder(nil, nil)
+	assert.EqualError(t, err, "connection reset")
+}
+
+func TestRequest_Respond(t *testing.T) {
+	r := Request{}
+	r.ReturnStatus(http.StatusCreated).
+		ReturnHeader("Content-Type", []string{"application/json"}).
+		Respond(func(req *http.Request, w ResponseBuilder) {
+			w.Body(`{"id": 42}`)
+		})
+
+	response, err := r.responder(nil, nil)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, response.Header)
+	assert.Equal(t, `{"id": 42}`, string(body))
+}
+
+func TestRespond(t *testing.T) {
+	mock := New(t).WithRequest(http.MethodGet, "/", Respond(func(req *http.Request, w ResponseBuilder) {
+		w.Status(http.StatusNoContent)
+	}))
+	r := mock.transport.requests[0]
+
+	response, err := r.responder(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Equal(t, http.Header{}, response.Header)
+}
+
+func TestRequest_acceptsMethod(t *testing.T) {
+	assert.True(t, newRequest(http.MethodGet, "/").acceptsMethod(http.MethodGet))
+	assert.False(t, newRequest(http.MethodGet, "/").acceptsMethod(http.MethodPost))
+	assert.False(t, newRequest("GET|POST", "/").acceptsMethod(http.MethodPost))
+
+	r := newRequest(http.MethodPost, "/")
+	r.methods = []string{http.MethodGet, http.MethodPost}
+	assert.True(t, r.acceptsMethod(http.MethodGet))
+	assert.True(t, r.acceptsMethod(http.MethodPost))
+	assert.False(t, r.acceptsMethod(http.MethodPut))
+}
@@ -0,0 +1,119 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type ResponseBuilder interface {
+	Status(status int) ResponseBuilder
+	Header(name string, values ...string) ResponseBuilder
+	Body(body string) ResponseBuilder
+	BodyFromObject(object interface{}) ResponseBuilder
+	Error(err error) ResponseBuilder
+}
+
+type responseBuilder struct {
+	status  int
+	headers http.Header
+	body    string
+	err     error
+}
+
+func (b *responseBuilder) Status(status int) ResponseBuilder {
+	b.status = status
+	return b
+}
+
+func (b *responseBuilder) Header(name string, values ...string) ResponseBuilder {
+	b.headers[http.CanonicalHeaderKey(name)] = values
+	return b
+}
+
+func (b *responseBuilder) Body(body string) ResponseBuilder {
+	b.body = body
+	return b
+}
+
+func (b *responseBuilder) BodyFromObject(object interface{}) ResponseBuilder {
+	body, _ := json.Marshal(&object)
+	b.body = string(body)
+	return b
+}
+
+func (b *responseBuilder) Error(err error) ResponseBuilder {
+	b.err = err
+	return b
+}
+
+func (b *responseBuilder) response() (*http.Response, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &http.Response{
+		StatusCode:    b.status,
+		Header:        b.headers,
+		ContentLength: int64(len(b.body)),
+		Body:          io.NopCloser(strings.NewReader(b.body)),
+	}, nil
+}
+
+func ReturnFunc(f func(*http.Request) (*http.Response, error)) RequestOption {
+	return func(r *Request) {
+		r.ReturnFunc(f)
+	}
+}
+
+func (r *Request) ReturnFunc(f func(*http.Request) (*http.Response, error)) *Request {
+	r.responder = func(req *http.Request, _ []byte) (*http.Response, error) {
+		return f(req)
+	}
+	return r
+}
+
+func Respond(f func(r *http.Request, w ResponseBuilder)) RequestOption {
+	return func(r *Request) {
+		r.Respond(f)
+	}
+}
+
+func (r *Request) Respond(f func(r *http.Request, w ResponseBuilder)) *Request {
+	r.responder = func(req *http.Request, _ []byte) (*http.Response, error) {
+		builder := &responseBuilder{
+			status:  r.returnStatus,
+			headers: r.returnHeaders.Clone(),
+			body:    r.returnBody,
+		}
+		if builder.headers == nil {
+			builder.headers = make(http.Header)
+		}
+		f(req, builder)
+		return builder.response()
+	}
+	return r
+}
+
+func completeResponse(response *http.Response, r *http.Request) *http.Response {
+	if response.StatusCode == 0 {
+		response.StatusCode = http.StatusOK
+	}
+	if response.Status == "" {
+		response.Status = http.StatusText(response.StatusCode)
+	}
+	if response.Proto == "" {
+		response.Proto, response.ProtoMajor, response.ProtoMinor = "HTTP/1.1", 1, 1
+	}
+	if response.Header == nil {
+		response.Header = make(http.Header)
+	}
+	if response.Body == nil {
+		response.Body = http.NoBody
+		response.ContentLength = 0
+	}
+	if response.Request == nil {
+		response.Request = r
+	}
+	return response
+}
@@ -0,0 +1,46 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_completeResponse(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+	response := completeResponse(&http.Response{}, r)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "OK", response.Status)
+	assert.Equal(t, "HTTP/1.1", response.Proto)
+	assert.Equal(t, http.Header{}, response.Header)
+	assert.Equal(t, http.NoBody, response.Body)
+	assert.Equal(t, r, response.Request)
+
+	response = completeResponse(&http.Response{StatusCode: http.StatusTeapot, Header: http.Header{"X-Id": {"1"}}}, r)
+	assert.Equal(t, http.StatusTeapot, response.StatusCode)
+	assert.Equal(t, "I'm a teapot", response.Status)
+	assert.Equal(t, http.Header{"X-Id": {"1"}}, response.Header)
+}
+
+func Test_responseBuilder(t *testing.T) {
+	builder := &responseBuilder{headers: http.Header{}}
+	builder.Status(http.StatusCreated).
+		Header("content-type", "application/json").
+		BodyFromObject(map[string]int{"id": 42})
+
+	response, err := builder.response()
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, response.Header)
+	assert.Equal(t, `{"id":42}`, string(body))
+	assert.Equal(t, int64(9), response.ContentLength)
+
+	builder.Body("raw").Error(errors.New("connection reset"))
+	_, err = builder.response()
+	assert.EqualError(t, err, "connection reset")
+}
@@ -0,0 +1,132 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func canonicalHost(scheme, host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.EqualFold(scheme, "http"):
+		return strings.TrimSuffix(host, ":80")
+	case strings.EqualFold(scheme, "https"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+func assertRoute(r *http.Request, req *Request) bool {
+	_, ok := matchRoute(r, req)
+	return ok
+}
+
+func matchRoute(r *http.Request, req *Request) (map[string]string, bool) {
+	if req.scheme != "" && !strings.EqualFold(req.scheme, r.URL.Scheme) {
+		return nil, false
+	}
+	if req.host != "" && canonicalHost(r.URL.Scheme, req.host) != canonicalHost(r.URL.Scheme, r.URL.Host) {
+		return nil, false
+	}
+	if req.urlRegexp != nil {
+		return matchRegexp(req.urlRegexp.FindStringSubmatch(r.URL.String()), req.urlRegexp.SubexpNames())
+	}
+
+	path := r.URL.Path
+	if path == "" && r.URL.Host != "" {
+		path = "/"
+	}
+	if req.pathRegexp != nil {
+		return matchRegexp(req.pathRegexp.FindStringSubmatch(path), req.pathRegexp.SubexpNames())
+	}
+	return matchPath(req.path, path)
+}
+
+func matchRegexp(submatches, names []string) (map[string]string, bool) {
+	if submatches == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	for i, name := range names {
+		if name != "" {
+			values[name] = submatches[i]
+		}
+	}
+	return values, true
+}
+
+func matchPath(pattern, path string) (map[string]string, bool) {
+	if !strings.Contains(pattern, "{") {
+		return nil, pattern == path
+	}
+
+	values := make(map[string]string)
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	for i, segment := range patternSegments {
+		if segment == "{$}" {
+			return values, i == len(pathSegments)-1 && pathSegments[i] == ""
+		}
+		if i >= len(pathSegments) {
+			return nil, false
+		}
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			if segment != pathSegments[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		if strings.HasSuffix(name, "...") {
+			values[strings.TrimSuffix(name, "...")] = strings.Join(pathSegments[i:], "/")
+			return values, true
+		}
+		if pathSegments[i] == "" {
+			return nil, false
+		}
+		values[name] = pathSegments[i]
+	}
+	return values, len(patternSegments) == len(pathSegments)
+}
+
+func routeDistance(r *http.Request, req *Request) int {
+	target, prefix := r.URL.Path, req.path
+	switch {
+	case req.urlRegexp != nil:
+		target, prefix = r.URL.String(), regexpLiteralPrefix(req.urlRegexp.String())
+	case req.pathRegexp != nil:
+		prefix = regexpLiteralPrefix(req.pathRegexp.String())
+	case strings.Contains(req.path, "{"):
+		prefix = req.path[:strings.Index(req.path, "{")]
+	}
+
+	common := 0
+	for common < len(target) && common < len(prefix) && target[common] == prefix[common] {
+		common++
+	}
+	distance := len(target) + len(prefix) - 2*common
+	if !req.acceptsMethod(r.Method) {
+		distance++
+	}
+	return distance
+}
+
+func regexpLiteralPrefix(expr string) string {
+	expr = strings.TrimPrefix(expr, "^")
+	if i := strings.IndexAny(expr, `\.+*?()|[]{}^$`); i >= 0 {
+		return expr[:i]
+	}
+	return expr
+}
+
+func requestRoute(r *http.Request) string {
+	u := url.URL{
+		Scheme: r.URL.Scheme,
+		Host:   r.URL.Host,
+		Path:   r.URL.Path,
+	}
+	return u.String()
+}
@@ -0,0 +1,65 @@
+package httpmock
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchPath(t *testing.T) {
+	tests := []struct {
+		name           string
+		pattern        string
+		path           string
+		expectedValues map[string]string
+		expectedMatch  bool
+	}{
+		{name: "literal", pattern: "/users", path: "/users", expectedMatch: true},
+		{name: "literal mismatch", pattern: "/users", path: "/users/1", expectedMatch: false},
+		{name: "wildcard", pattern: "/users/{id}", path: "/users/42", expectedValues: map[string]string{"id": "42"}, expectedMatch: true},
+		{name: "wildcards", pattern: "/users/{id}/posts/{post}", path: "/users/42/posts/7", expectedValues: map[string]string{"id": "42", "post": "7"}, expectedMatch: true},
+		{name: "wildcard too short", pattern: "/users/{id}", path: "/users", expectedMatch: false},
+		{name: "wildcard too long", pattern: "/users/{id}", path: "/users/42/posts", expectedMatch: false},
+		{name: "wildcard empty segment", pattern: "/users/{id}", path: "/users/", expectedMatch: false},
+		{name: "remainder", pattern: "/files/{path...}", path: "/files/a/b/c.txt", expectedValues: map[string]string{"path": "a/b/c.txt"}, expectedMatch: true},
+		{name: "empty remainder", pattern: "/files/{path...}", path: "/files/", expectedValues: map[string]string{"path": ""}, expectedMatch: true},
+		{name: "end anchor", pattern: "/files/{$}", path: "/files/", expectedValues: map[string]string{}, expectedMatch: true},
+		{name: "end anchor mismatch", pattern: "/files/{$}", path: "/files/a", expectedMatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, ok := matchPath(tt.pattern, tt.path)
+
+			assert.Equal(t, tt.expectedMatch, ok)
+			if tt.expectedMatch {
+				assert.Equal(t, tt.expectedValues, values)
+			}
+		})
+	}
+}
+
+func Test_matchRoute_regexp(t *testing.T) {
+	req := &Request{pathRegexp: regexp.MustCompile(`^/v(?P<version>\d+)/users$`)}
+
+	r, _ := http.NewRequest(http.MethodGet, "https://api.example.com/v2/users", nil)
+	values, ok := matchRoute(r, req)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"version": "2"}, values)
+
+	r, _ = http.NewRequest(http.MethodGet, "https://api.example.com/v2/groups", nil)
+	_, ok = matchRoute(r, req)
+	assert.False(t, ok)
+
+	req = &Request{urlRegexp: regexp.MustCompile(`^https://bucket\.s3\.amazonaws\.com/.+\?X-Amz-Signature=`)}
+	r, _ = http.NewRequest(http.MethodGet, "https://bucket.s3.amazonaws.com/some/key?X-Amz-Signature=abc", nil)
+	_, ok = matchRoute(r, req)
+	assert.True(t, ok)
+}
+
+func Test_regexpLiteralPrefix(t *testing.T) {
+	assert.Equal(t, "/v", regexpLiteralPrefix(`^/v\d+/users$`))
+	assert.Equal(t, "/bucket/", regexpLiteralPrefix(`/bucket/.+`))
+	assert.Equal(t, "/users", regexpLiteralPrefix(`/users`))
+}
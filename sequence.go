@@ -0,0 +1,44 @@
+package httpmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Sequence struct {
+	requests []*Request
+}
+
+func (c *Client) InOrder(requests ...*Request) *Sequence {
+	sequence := &Sequence{requests: requests}
+	c.transport.sequences = append(c.transport.sequences, sequence)
+	return sequence
+}
+
+func (s *Sequence) pending(req *Request) *Request {
+	var pending *Request
+	for _, previous := range s.requests {
+		if previous == req {
+			return pending
+		}
+		if pending == nil && previous.timesCalled < previous.expectedTimesCalled {
+			pending = previous
+		}
+	}
+	return nil
+}
+
+func (t *transport) outOfOrder(req *Request) string {
+	for _, sequence := range t.sequences {
+		if pending := sequence.pending(req); pending != nil {
+			builder := strings.Builder{}
+			builder.WriteString(fmt.Sprintf("[%s] %q should be called x%d before\n", pending.method, pending.route(), pending.expectedTimesCalled-pending.timesCalled))
+			builder.WriteString("Call timeline:\n")
+			for i, call := range t.timeline {
+				builder.WriteString(fmt.Sprintf("\t%d. %s\n", i+1, call))
+			}
+			return builder.String()
+		}
+	}
+	return ""
+}
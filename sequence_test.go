@@ -0,0 +1,41 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequence_pending(t *testing.T) {
+	mock := New(t)
+	login := mock.On(http.MethodPost, "/login").Times(2)
+	profile := mock.On(http.MethodGet, "/profile")
+	logout := mock.On(http.MethodDelete, "/session")
+	sequence := mock.InOrder(login, profile, logout)
+
+	assert.Nil(t, sequence.pending(login))
+	assert.Equal(t, login, sequence.pending(profile))
+	assert.Equal(t, login, sequence.pending(logout))
+
+	login.timesCalled = 2
+	assert.Nil(t, sequence.pending(profile))
+	assert.Equal(t, profile, sequence.pending(logout))
+	assert.Nil(t, sequence.pending(mock.On(http.MethodGet, "/health")))
+}
+
+func Test_transport_outOfOrder(t *testing.T) {
+	mock := New(t)
+	login := mock.On(http.MethodPost, "/login")
+	profile := mock.On(http.MethodGet, "/profile")
+	settings := mock.On(http.MethodGet, "/settings")
+	logout := mock.On(http.MethodDelete, "/session")
+	mock.InOrder(login, profile)
+	mock.InOrder(settings, logout)
+
+	mock.transport.timeline = []string{`[POST] "/login"`, `[DELETE] "/session"`}
+	login.timesCalled = 1
+
+	assert.Equal(t, "", mock.transport.outOfOrder(profile))
+	assert.Equal(t, "[GET] \"/settings\" should be called x1 before\nCall timeline:\n\t1. [POST] \"/login\"\n\t2. [DELETE] \"/session\"\n", mock.transport.outOfOrder(logout))
+}
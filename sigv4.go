@@ -0,0 +1,172 @@
+package httpmock
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type sigV4Matcher struct {
+	accessKey string
+	secretKey string
+	region    string
+	service   string
+}
+
+func SigV4(accessKey, secretKey, region, service string) Matcher {
+	return sigV4Matcher{accessKey: accessKey, secretKey: secretKey, region: region, service: service}
+}
+
+func (m sigV4Matcher) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m sigV4Matcher) signsSentBody() {}
+
+func (m sigV4Matcher) String() string {
+	return fmt.Sprintf("AWS SigV4 signature by %s for %s in %s", m.accessKey, m.service, m.region)
+}
+
+func (m sigV4Matcher) explain(r *http.Request) []string {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 ") {
+		return []string{"SigV4: Authorization header is not AWS4-HMAC-SHA256"}
+	}
+
+	fields := make(map[string]string)
+	for _, field := range strings.Split(strings.TrimPrefix(authorization, "AWS4-HMAC-SHA256 "), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		fields[name] = value
+	}
+
+	credential := strings.Split(fields["Credential"], "/")
+	if len(credential) != 5 || credential[4] != "aws4_request" {
+		return []string{fmt.Sprintf("SigV4: malformed credential %q", fields["Credential"])}
+	}
+	expectedScope := []string{m.accessKey, credential[1], m.region, m.service, "aws4_request"}
+	if strings.Join(credential, "/") != strings.Join(expectedScope, "/") {
+		return []string{fmt.Sprintf("SigV4: expected credential %s, got %s", strings.Join(expectedScope, "/"), strings.Join(credential, "/"))}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate == "" {
+		return []string{"SigV4: X-Amz-Date header missing"}
+	}
+	if !strings.HasPrefix(amzDate, credential[1]) {
+		return []string{fmt.Sprintf("SigV4: credential date %s does not match X-Amz-Date %s", credential[1], amzDate)}
+	}
+
+	canonicalRequest := sigV4CanonicalRequest(r, requestBody(r), strings.Split(fields["SignedHeaders"], ";"), m.service)
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		strings.Join(credential[1:], "/"),
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + m.secretKey)
+	for _, part := range []string{credential[1], m.region, m.service, "aws4_request"} {
+		key = hmacSHA256Sum(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256Sum(key, stringToSign))
+	if signature != fields["Signature"] {
+		return []string{fmt.Sprintf(
+			"SigV4: expected signature %s, got %s\n\t\tCanonical request:\n\t\t\t%s\n\t\tString to sign:\n\t\t\t%s",
+			signature,
+			fields["Signature"],
+			strings.ReplaceAll(canonicalRequest, "\n", "\n\t\t\t"),
+			strings.ReplaceAll(stringToSign, "\n", "\n\t\t\t"),
+		)}
+	}
+	return nil
+}
+
+func sigV4CanonicalRequest(r *http.Request, body []byte, signedHeaders []string, service string) string {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			unescaped = segment
+		}
+		segments[i] = sigV4Escape(unescaped)
+		if service != "s3" {
+			segments[i] = sigV4Escape(segments[i])
+		}
+	}
+
+	query := r.URL.Query()
+	queryPairs := make([][2]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			queryPairs = append(queryPairs, [2]string{sigV4Escape(name), sigV4Escape(value)})
+		}
+	}
+	sort.Slice(queryPairs, func(i, j int) bool {
+		if queryPairs[i][0] != queryPairs[j][0] {
+			return queryPairs[i][0] < queryPairs[j][0]
+		}
+		return queryPairs[i][1] < queryPairs[j][1]
+	})
+	queryParts := make([]string, 0, len(queryPairs))
+	for _, pair := range queryPairs {
+		queryParts = append(queryParts, pair[0]+"="+pair[1])
+	}
+
+	headers := make([]string, 0, len(signedHeaders))
+	for _, name := range signedHeaders {
+		values := append([]string(nil), r.Header.Values(name)...)
+		if strings.EqualFold(name, "host") {
+			values = []string{r.Host}
+			if r.Host == "" {
+				values = []string{r.URL.Host}
+			}
+		}
+		for i, value := range values {
+			values[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers = append(headers, strings.ToLower(name)+":"+strings.Join(values, ","))
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		strings.Join(segments, "/"),
+		strings.Join(queryParts, "&"),
+		strings.Join(headers, "\n") + "\n",
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func sigV4Escape(s string) string {
+	builder := strings.Builder{}
+	for _, b := range []byte(s) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || strings.IndexByte("-_.~", b) >= 0 {
+			builder.WriteByte(b)
+		} else {
+			builder.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return builder.String()
+}
+
+func hmacSHA256Sum(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -1,63 +1,111 @@
 package httpmock
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
 )
 
 var UnexpectedRequestErr = fmt.Errorf("unexpected request")
+var NoResponseErr = fmt.Errorf("no response returned")
 
 type transport struct {
-	m        sync.Mutex
-	t        *testing.T
-	requests []*Request
+	m              sync.Mutex
+	t              *testing.T
+	requests       []*Request
+	mostSpecific   bool
+	laterOverrides bool
+	sequences      []*Sequence
+	timeline       []string
 }
 
-func assertHeaders(r *http.Request, req *Request) bool {
-	for name, values := range req.expectedHeaders {
-		requestValues, ok := r.Header[name]
-		if !ok {
-			return false
-		}
-		if !reflect.DeepEqual(values, requestValues) {
-			return false
-		}
+func newCall(r *http.Request, body []byte, req *Request) Call {
+	call := Call{
+		Request: r.Clone(r.Context()),
+		Body:    body,
 	}
-	return true
+	call.Request.Body = io.NopCloser(bytes.NewReader(body))
+	call.Request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	call.PathValues, _ = matchRoute(r, req)
+	for name, value := range call.PathValues {
+		call.Request.SetPathValue(name, value)
+	}
+	return call
 }
 
-func assertQueryParams(r *http.Request, req *Request) bool {
-	requestQuery := r.URL.Query()
-	for name, values := range req.expectedQueryParams {
-		requestValues := requestQuery[name]
-		if !reflect.DeepEqual(values, requestValues) {
-			return false
-		}
+func headerMismatches(r *http.Request, body []byte, req *Request) []string {
+	var mismatches []string
+	for _, name := range sortedKeys(req.expectedHeaders) {
+		mismatches = append(mismatches, headerMatcher{name: name, values: req.expectedHeaders[name]}.explain(r)...)
 	}
-	return true
+	for _, matcher := range req.headerMatchers {
+		mismatches = append(mismatches, explain(matcher, r, body)...)
+	}
+	return mismatches
 }
 
-func assertJSON(r *http.Request, req *Request) bool {
-	if len(req.expectedJSON) > 0 {
-		if r.Body == nil {
-			return false
+func sameValues(expected, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	sortedExpected := append([]string(nil), expected...)
+	sortedActual := append([]string(nil), actual...)
+	sort.Strings(sortedExpected)
+	sort.Strings(sortedActual)
+	return reflect.DeepEqual(sortedExpected, sortedActual)
+}
+
+func queryParamMismatches(r *http.Request, req *Request) []string {
+	var mismatches []string
+	for _, name := range sortedKeys(req.expectedQueryParams) {
+		mismatches = append(mismatches, queryParamMatcher{name: name, values: req.expectedQueryParams[name]}.explain(r)...)
+	}
+	for _, matcher := range req.queryMatchers {
+		mismatches = append(mismatches, explain(matcher, r, nil)...)
+	}
+
+	if req.exactQuery {
+		expected := make(map[string]bool)
+		for name := range req.expectedQueryParams {
+			expected[name] = true
 		}
-		actual, err := io.ReadAll(r.Body)
-		if err != nil {
-			return false
+		for _, matcher := range req.queryMatchers {
+			switch m := matcher.(type) {
+			case queryParamValueMatcher:
+				expected[m.name] = true
+			case apiKeyMatcher:
+				expected[m.name] = true
+			}
+		}
+
+		query := r.URL.Query()
+		for _, name := range sortedKeys(query) {
+			if !expected[name] {
+				mismatches = append(mismatches, fmt.Sprintf("query param %s: unexpected %s", name, query[name]))
+			}
 		}
+	}
+	return mismatches
+}
 
+func assertJSON(body []byte, req *Request) bool {
+	if len(req.expectedJSON) > 0 {
 		var expectedJSONAsInterface, actualJSONAsInterface interface{}
 		if err := json.Unmarshal(req.expectedJSON, &expectedJSONAsInterface); err != nil {
 			return false
 		}
-		if err := json.Unmarshal(actual, &actualJSONAsInterface); err != nil {
+		if err := json.Unmarshal(body, &actualJSONAsInterface); err != nil {
 			return false
 		}
 
@@ -68,60 +116,376 @@ func assertJSON(r *http.Request, req *Request) bool {
 	return true
 }
 
-func assertBody(r *http.Request, req *Request) bool {
+func assertBody(body []byte, req *Request) bool {
 	if len(req.expectedBody) > 0 {
-		if r.Body == nil {
+		if req.expectedBody != string(body) {
 			return false
 		}
-		data, err := io.ReadAll(r.Body)
-		if err != nil {
-			return false
+	}
+	return true
+}
+
+func jsonSubsetMismatchesFromBody(body []byte, req *Request) []string {
+	if len(req.expectedJSONSubset) == 0 {
+		return nil
+	}
+
+	var expected, actual interface{}
+	if err := json.Unmarshal(req.expectedJSONSubset, &expected); err != nil {
+		return []string{fmt.Sprintf("invalid expected JSON subset: %s", err)}
+	}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		return []string{fmt.Sprintf("body is not valid JSON: %s", err)}
+	}
+	return jsonSubsetMismatches(expected, actual, "$")
+}
+
+func jsonPathMismatches(body []byte, req *Request) []string {
+	if len(req.expectedJSONPaths) == 0 {
+		return nil
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return []string{fmt.Sprintf("body is not valid JSON: %s", err)}
+	}
+
+	var mismatches []string
+	for _, expectation := range req.expectedJSONPaths {
+		if mismatch := expectation.mismatch(document); mismatch != "" {
+			mismatches = append(mismatches, mismatch)
 		}
-		if req.expectedBody != string(data) {
-			return false
+	}
+	return mismatches
+}
+
+func mismatches(r *http.Request, body []byte, req *Request) []string {
+	var reasons []string
+	if !req.acceptsMethod(r.Method) {
+		expected := req.method
+		if len(req.methods) > 0 {
+			expected = strings.Join(req.methods, " or ")
 		}
+		reasons = append(reasons, fmt.Sprintf("method: expected %s, got %s", expected, r.Method))
 	}
-	return true
+	if !assertBody(body, req) {
+		reasons = append(reasons, "body is not equal")
+	}
+	if !assertJSON(body, req) {
+		reasons = append(reasons, "JSON is not equal")
+	}
+	reasons = append(reasons, jsonSubsetMismatchesFromBody(body, req)...)
+	reasons = append(reasons, jsonPathMismatches(body, req)...)
+	reasons = append(reasons, xmlBodyMismatches(body, req)...)
+	reasons = append(reasons, formMismatches(body, req)...)
+	for _, matcher := range req.graphQLMatchers {
+		reasons = append(reasons, explain(matcher, r, body)...)
+	}
+	for _, matcher := range req.jsonRPCMatchers {
+		reasons = append(reasons, explain(matcher, r, body)...)
+	}
+	reasons = append(reasons, multipartMismatches(r, body, req)...)
+	reasons = append(reasons, headerMismatches(r, body, req)...)
+	reasons = append(reasons, queryParamMismatches(r, req)...)
+	for _, matcher := range req.matchers {
+		reasons = append(reasons, explain(matcher, r, body)...)
+	}
+	return reasons
 }
 
-func (t *transport) matchRequest(r *http.Request) (*Request, *Request) {
-	var closestReq *Request
-	for _, req := range t.requests {
-		if req.timesCalled < req.expectedTimesCalled && req.path == r.URL.Path {
-			if req.method == r.Method && assertJSON(r, req) && assertBody(r, req) && assertHeaders(r, req) && assertQueryParams(r, req) {
-				return req, nil
+func formMismatches(body []byte, req *Request) []string {
+	if len(req.expectedForm) == 0 && !req.exactForm {
+		return nil
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return []string{fmt.Sprintf("body is not a valid form: %s", err)}
+	}
+
+	var mismatches []string
+	for _, name := range sortedKeys(req.expectedForm) {
+		values, ok := form[name]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("form field %s: missing", name))
+			continue
+		}
+		if !reflect.DeepEqual(req.expectedForm[name], values) {
+			mismatches = append(mismatches, fmt.Sprintf("form field %s: expected %s, got %s", name, req.expectedForm[name], values))
+		}
+	}
+	if req.exactForm {
+		for _, name := range sortedKeys(form) {
+			if _, ok := req.expectedForm[name]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("form field %s: unexpected %s", name, form[name]))
 			}
+		}
+	}
+	return mismatches
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
+
+func (t *transport) matchRequest(r *http.Request, body []byte) (*Request, *Request) {
+	var matchedReq, closestReq *Request
+	for i := range t.requests {
+		req := t.requests[i]
+		if t.laterOverrides {
+			req = t.requests[len(t.requests)-1-i]
+		}
+		if req.timesCalled >= req.expectedTimesCalled || !assertRoute(r, req) {
+			continue
+		}
+		if len(mismatches(r, body, req)) > 0 {
 			closestReq = req
+			continue
+		}
+		if matchedReq == nil || t.prefer(req, matchedReq) {
+			matchedReq = req
 		}
 	}
+	if matchedReq != nil {
+		return matchedReq, nil
+	}
 	return nil, closestReq
 }
 
-func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+func (t *transport) prefer(req, other *Request) bool {
+	if req.priority != other.priority {
+		return req.priority > other.priority
+	}
+	return t.mostSpecific && req.specificity() > other.specificity()
+}
+
+func (t *transport) nearestRequest(r *http.Request) *Request {
+	var nearestReq *Request
+	nearestDistance := 0
+	for _, req := range t.requests {
+		if req.timesCalled >= req.expectedTimesCalled {
+			continue
+		}
+		if distance := routeDistance(r, req); nearestReq == nil || distance < nearestDistance {
+			nearestReq, nearestDistance = req, distance
+		}
+	}
+	return nearestReq
+}
+
+func (t *transport) hasJSONRPCRoute(r *http.Request) bool {
+	for _, req := range t.requests {
+		if len(req.jsonRPCMatchers) > 0 && assertRoute(r, req) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *transport) find(r *http.Request, body []byte) (*Request, error) {
 	t.t.Helper()
-	t.m.Lock()
-	defer t.m.Unlock()
+	t.timeline = append(t.timeline, fmt.Sprintf("[%s] %q", r.Method, requestRoute(r)))
 
-	req, closestReq := t.matchRequest(r)
+	req, closestReq := t.matchRequest(r, body)
 	if closestReq != nil {
-		t.t.Errorf("Unexpected request on route [%s] %q the closest request I have is:\n%s", r.Method, r.URL.Path, closestReq.String())
+		message := fmt.Sprintf("Unexpected request on route [%s] %q the closest request I have is:\n%s", r.Method, requestRoute(r), closestReq.String())
+		if len(r.URL.RawQuery) > 0 {
+			message += fmt.Sprintf("Received query:\n\t%q\n", redactQuery(r.URL.RawQuery, closestReq))
+		}
+		if len(body) > 0 {
+			message += fmt.Sprintf("Received body:\n\t%q\n", string(body))
+		}
+		if reasons := mismatches(r, body, closestReq); len(reasons) > 0 {
+			message += "Mismatches:\n"
+			for _, reason := range reasons {
+				message += fmt.Sprintf("\t- %s\n", reason)
+			}
+		}
+		t.t.Error(message)
 		return nil, UnexpectedRequestErr
 	}
 	if req == nil {
-		t.t.Errorf("Unexpected request on route [%s] %q", r.Method, r.URL.Path)
+		if nearestReq := t.nearestRequest(r); nearestReq != nil {
+			t.t.Errorf("Unexpected request on route [%s] %q the nearest route I have is:\n%s", r.Method, requestRoute(r), nearestReq.String())
+			return nil, UnexpectedRequestErr
+		}
+		t.t.Errorf("Unexpected request on route [%s] %q", r.Method, requestRoute(r))
 		return nil, UnexpectedRequestErr
 	}
+	if message := t.outOfOrder(req); message != "" {
+		t.t.Errorf("Out of order request on route [%s] %q: %s", r.Method, requestRoute(r), message)
+		return nil, UnexpectedRequestErr
+	}
+	return req, nil
+}
+
+func (t *transport) record(r *http.Request, body []byte, req *Request) {
+	req.calls = append(req.calls, newCall(r, body, req))
+	for _, capture := range req.captures {
+		capture(body)
+	}
+}
+
+func (t *transport) handle(r *http.Request, body []byte) (*Request, error) {
+	t.t.Helper()
+
+	req, err := t.find(r, body)
+	if err != nil {
+		return nil, err
+	}
 	req.timesCalled += 1
+	t.record(r, body, req)
 
 	if req.returnError != nil {
 		return nil, req.returnError
 	}
+	return req, nil
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.t.Helper()
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	sent, err := readBody(r)
+	if err != nil {
+		t.t.Errorf("Could not read request body on route [%s] %q: %s", r.Method, requestRoute(r), err)
+		return nil, err
+	}
+	body, err := decodeBody(r, sent)
+	if err != nil {
+		t.t.Errorf("Invalid request body on route [%s] %q: %s", r.Method, requestRoute(r), err)
+		return nil, err
+	}
+	r = withBody(r, sent)
+
+	if t.hasJSONRPCRoute(r) {
+		if elements, ok := jsonRPCBatch(r, body); ok {
+			return t.roundTripJSONRPCBatch(r, elements)
+		}
+	}
+
+	req, err := t.handle(r, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.responder != nil {
+		response, err := req.responder(newCall(r, body, req).Request, body)
+		if err != nil {
+			return nil, err
+		}
+		if response == nil {
+			t.t.Errorf("No response returned on route [%s] %q", r.Method, requestRoute(r))
+			return nil, NoResponseErr
+		}
+		return completeResponse(response, r), nil
+	}
+
+	returnBody, contentLength := req.returnBody, req.ContentLength()
+	if req.jsonRPC {
+		returnBody = req.jsonRPCResponseBody(body)
+		contentLength = int64(len(returnBody))
+	}
 
 	return &http.Response{
 		Status:        http.StatusText(req.returnStatus),
 		StatusCode:    req.returnStatus,
 		Header:        req.returnHeaders,
-		ContentLength: req.ContentLength(),
-		Body:          io.NopCloser(strings.NewReader(req.returnBody)),
+		ContentLength: contentLength,
+		Body:          io.NopCloser(strings.NewReader(returnBody)),
+	}, nil
+}
+
+func (t *transport) roundTripJSONRPCBatch(r *http.Request, elements []json.RawMessage) (*http.Response, error) {
+	t.t.Helper()
+
+	reqs := make([]*Request, 0, len(elements))
+	for _, element := range elements {
+		req, err := t.find(withBody(r, element), element)
+		if err != nil {
+			for _, matched := range reqs {
+				matched.timesCalled -= 1
+			}
+			return nil, err
+		}
+		req.timesCalled += 1
+		reqs = append(reqs, req)
+	}
+
+	responses := make([]json.RawMessage, 0, len(elements))
+	for i, req := range reqs {
+		t.record(withBody(r, elements[i]), elements[i], req)
+		if req.returnError != nil {
+			return nil, req.returnError
+		}
+
+		response, err := t.jsonRPCBatchResponse(r, elements[i], req)
+		if err != nil {
+			return nil, err
+		}
+		if len(response) > 0 {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		return &http.Response{
+			Status:     http.StatusText(http.StatusNoContent),
+			StatusCode: http.StatusNoContent,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}, nil
+	}
+
+	returnBody, err := json.Marshal(responses)
+	if err != nil {
+		t.t.Errorf("Invalid JSON-RPC batch response on route [%s] %q: %s", r.Method, requestRoute(r), err)
+		return nil, err
+	}
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		ContentLength: int64(len(returnBody)),
+		Body:          io.NopCloser(bytes.NewReader(returnBody)),
 	}, nil
 }
+
+func (t *transport) jsonRPCBatchResponse(r *http.Request, element []byte, req *Request) (json.RawMessage, error) {
+	t.t.Helper()
+
+	switch {
+	case req.responder != nil:
+		response, err := req.responder(newCall(withBody(r, element), element, req).Request, element)
+		if err != nil {
+			return nil, err
+		}
+		if response == nil {
+			t.t.Errorf("No response returned on route [%s] %q", r.Method, requestRoute(r))
+			return nil, NoResponseErr
+		}
+		if response.Body == nil {
+			return nil, nil
+		}
+		defer response.Body.Close()
+		return io.ReadAll(response.Body)
+	case req.jsonRPC:
+		return json.RawMessage(req.jsonRPCResponseBody(element)), nil
+	default:
+		return json.RawMessage(req.returnBody), nil
+	}
+}
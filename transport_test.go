@@ -0,0 +1,164 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_transport_nearestRequest(t *testing.T) {
+	mock := New(t)
+	users := mock.OnRegexp(http.MethodGet, regexp.MustCompile(`^/v\d+/users$`))
+	mock.On(http.MethodGet, "/health")
+	mock.On(http.MethodGet, "/files/{path...}")
+
+	r, _ := http.NewRequest(http.MethodGet, "/v1/user", nil)
+	assert.Equal(t, users, mock.transport.nearestRequest(r))
+
+	r, _ = http.NewRequest(http.MethodGet, "/file", nil)
+	assert.Equal(t, mock.transport.requests[2], mock.transport.nearestRequest(r))
+
+	assert.Equal(t, "Request: [GET] \"regexp(^/v\\\\d+/users$)\"\n", users.String())
+}
+
+func Test_transport_matchRequest(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/users?dry=true", nil)
+	body := []byte(`{"name": "john"}`)
+
+	mock := New(t)
+	broad := mock.OnRegexp(http.MethodPost, regexp.MustCompile(`^/users`))
+	narrow := mock.On(http.MethodPost, "/users").ExpectQueryParam("dry", "true").ExpectJSON(`{"name": "john"}`)
+	mock.On(http.MethodPost, "/users").ExpectQueryParam("dry", "false")
+
+	req, closestReq := mock.transport.matchRequest(r, body)
+	assert.Equal(t, broad, req)
+	assert.Nil(t, closestReq)
+
+	mock.MostSpecificMatch()
+	req, _ = mock.transport.matchRequest(r, body)
+	assert.Equal(t, narrow, req)
+
+	broad.Priority(1)
+	req, _ = mock.transport.matchRequest(r, body)
+	assert.Equal(t, broad, req)
+}
+
+func Test_transport_matchRequest_later_overrides(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/users", nil)
+
+	mock := New(t).LaterOverrides()
+	defaults := mock.On(http.MethodGet, "/users").Times(10)
+	override := mock.On(http.MethodGet, "/users")
+
+	req, _ := mock.transport.matchRequest(r, nil)
+	assert.Equal(t, override, req)
+
+	override.timesCalled = 1
+	req, _ = mock.transport.matchRequest(r, nil)
+	assert.Equal(t, defaults, req)
+
+	defaults.Priority(1)
+	override.timesCalled = 0
+	req, _ = mock.transport.matchRequest(r, nil)
+	assert.Equal(t, defaults, req)
+}
+
+func TestRequest_specificity(t *testing.T) {
+	assert.Equal(t, 0, newRequest(http.MethodGet, "").specificity())
+	assert.Equal(t, 1, newRequest(http.MethodGet, "/users/{id}").specificity())
+	assert.Equal(t, 2, newRequest(http.MethodGet, "/users").specificity())
+	assert.Equal(t, 4, newRequest(http.MethodGet, "https://example.com/users").specificity())
+	assert.Equal(t, 5, newRequest(http.MethodGet, "/users").
+		ExpectHeader("Accept", []string{"application/json"}).
+		ExpectQueryParam("page", "1").
+		ExpectJSON(`{}`).
+		specificity())
+}
+
+func Test_mismatches(t *testing.T) {
+	req := newRequest(http.MethodPost, "/items").
+		ExpectJSONSubset(`{"name": "first", "tags": {"color": "red"}}`).
+		ExpectQueryParam("dry", "true")
+
+	r, _ := http.NewRequest(http.MethodPut, "/items", nil)
+	body := []byte(`{"id": 1, "name": "second", "tags": {}}`)
+
+	assert.Equal(t, []string{
+		"method: expected POST, got PUT",
+		`$.name: expected "first", got "second"`,
+		"$.tags.color: missing",
+		"query param dry: missing",
+	}, mismatches(r, body, req))
+}
+
+func Test_mismatches_json_paths(t *testing.T) {
+	req := newRequest(http.MethodPost, "/orders").
+		ExpectJSONPath("$.items[0].sku", "ABC").
+		ExpectJSONPathLen("$.items", 2).
+		ExpectJSONPathExists("$.customer.id")
+
+	r, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+	body := []byte(`{"items": [{"sku": "XYZ"}], "customer": {"id": 1}}`)
+
+	assert.Equal(t, []string{
+		`$.items[0].sku: expected "ABC", got "XYZ"`,
+		"$.items: expected length 2, got 1",
+	}, mismatches(r, body, req))
+}
+
+func Test_formMismatches(t *testing.T) {
+	req := newRequest(http.MethodPost, "/token").
+		ExpectFormValue("grant_type", "client_credentials").
+		ExpectFormValues(url.Values{"scope": {"read", "write"}})
+
+	assert.Empty(t, formMismatches([]byte("scope=read&scope=write&client_id=abc&grant_type=client_credentials"), req))
+	assert.Equal(t, []string{
+		"form field grant_type: missing",
+		"form field scope: expected [read write], got [write read]",
+	}, formMismatches([]byte("scope=write&scope=read"), req))
+
+	req.ExpectExactForm()
+	assert.Equal(t, []string{
+		"form field client_id: unexpected [abc]",
+	}, formMismatches([]byte("client_id=abc&scope=read&scope=write&grant_type=client_credentials"), req))
+}
+
+func Test_queryParamMismatches(t *testing.T) {
+	req := newRequest(http.MethodGet, "/search").
+		ExpectQueryParam("q", "shoes").
+		ExpectQueryParamRegexp("page", regexp.MustCompile(`^\d+$`)).
+		ExpectNoQueryParam("debug")
+
+	r, _ := http.NewRequest(http.MethodGet, "/search?q=shoes&page=2&sort=price", nil)
+	assert.Empty(t, queryParamMismatches(r, req))
+
+	r, _ = http.NewRequest(http.MethodGet, "/search?q=boots&page=two&debug=1", nil)
+	assert.Equal(t, []string{
+		"query param q: expected [shoes], got [boots]",
+		`query param page: expected a value matching regexp ^\d+$, got [two]`,
+		"query param debug: expected absent, got [1]",
+	}, queryParamMismatches(r, req))
+
+	r, _ = http.NewRequest(http.MethodGet, "/search?q=shoes&page=two&page=2", nil)
+	assert.Empty(t, queryParamMismatches(r, req))
+
+	req.ExpectExactQuery()
+	r, _ = http.NewRequest(http.MethodGet, "/search?q=shoes&page=2&sort=price&limit=10", nil)
+	assert.Equal(t, []string{
+		"query param limit: unexpected [10]",
+		"query param sort: unexpected [price]",
+	}, queryParamMismatches(r, req))
+}
+
+func Test_mismatches_methods(t *testing.T) {
+	mock := New(t)
+	req := mock.OnGraphQL("GetUser")
+
+	r, _ := http.NewRequest(http.MethodPut, "/graphql", nil)
+	assert.Equal(t, "method: expected GET or POST, got PUT", mismatches(r, []byte(`{"query": "query GetUser { id }"}`), req)[0])
+	assert.Equal(t, "Request: [POST] \"/graphql\"\n", strings.SplitAfter(req.String(), "\n")[0])
+}
@@ -0,0 +1,91 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+type jsonAsMatcher[T any] struct {
+	check func(T) error
+}
+
+func JSONAs[T any](check func(T) error) Matcher {
+	return jsonAsMatcher[T]{check: check}
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+func decodeJSONAs[T any](body []byte) (T, error) {
+	var value T
+	if err := json.Unmarshal(body, &value); err != nil {
+		return value, fmt.Errorf("body is not a valid %s: %w", typeName[T](), err)
+	}
+	return value, nil
+}
+
+func (m jsonAsMatcher[T]) Match(r *http.Request) bool {
+	return len(m.explain(r)) == 0
+}
+
+func (m jsonAsMatcher[T]) String() string {
+	return fmt.Sprintf("JSON body as %s", typeName[T]())
+}
+
+func (m jsonAsMatcher[T]) explain(r *http.Request) []string {
+	value, err := decodeJSONAs[T](requestBody(r))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if m.check != nil {
+		if err := m.check(value); err != nil {
+			return []string{fmt.Sprintf("JSON body as %s: %s", typeName[T](), err)}
+		}
+	}
+	return nil
+}
+
+func ExpectJSONAs[T any](check func(T) error) RequestOption {
+	return Match(JSONAs(check))
+}
+
+func CaptureJSON[T any](v *T) RequestOption {
+	return func(r *Request) {
+		r.Match(JSONAs[T](nil))
+		r.captures = append(r.captures, func(body []byte) {
+			*v, _ = decodeJSONAs[T](body)
+		})
+	}
+}
+
+func HandleJSON[Req, Resp any](client *Client, method, path string, handler func(Req) (Resp, int)) *Request {
+	req := client.On(method, path).Match(JSONAs[Req](nil))
+	req.responder = func(r *http.Request, body []byte) (*http.Response, error) {
+		value, err := decodeJSONAs[Req](body)
+		if err != nil {
+			return nil, err
+		}
+
+		response, status := handler(value)
+		if status == 0 {
+			status = http.StatusOK
+		}
+		encoded, err := json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        http.StatusText(status),
+			StatusCode:    status,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			ContentLength: int64(len(encoded)),
+			Body:          io.NopCloser(bytes.NewReader(encoded)),
+		}, nil
+	}
+	return req
+}
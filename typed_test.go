@@ -0,0 +1,30 @@
+package httpmock
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func Test_JSONAs(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "/users", nil)
+	adult := func(user typedUser) error {
+		if user.Age < 18 {
+			return errors.New("user is not an adult")
+		}
+		return nil
+	}
+
+	assert.Empty(t, explain(JSONAs(adult), r, []byte(`{"name": "john", "age": 42}`)))
+	assert.Empty(t, explain(JSONAs[typedUser](nil), r, []byte(`{"name": "john"}`)))
+	assert.Equal(t, []string{"JSON body as httpmock.typedUser: user is not an adult"}, explain(JSONAs(adult), r, []byte(`{"name": "john", "age": 12}`)))
+	assert.Equal(t, []string{"body is not a valid httpmock.typedUser: json: cannot unmarshal string into Go struct field typedUser.age of type int"}, explain(JSONAs(adult), r, []byte(`{"age": "42"}`)))
+	assert.Equal(t, "JSON body as []string", JSONAs[[]string](nil).String())
+}
@@ -0,0 +1,221 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type xmlNode struct {
+	name     xml.Name
+	attrs    map[xml.Name]string
+	text     string
+	children []*xmlNode
+}
+
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: make(map[xml.Name]string)}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" && attr.Name.Space == "" {
+					continue
+				}
+				node.attrs[attr.Name] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				if text := strings.TrimSpace(string(t)); text != "" {
+					stack[len(stack)-1].text += text
+				}
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return fmt.Sprintf("{%s}%s", name.Space, name.Local)
+}
+
+func xmlMismatches(expected, actual *xmlNode, path string) []string {
+	if expected.name != actual.name {
+		return []string{fmt.Sprintf("%s: expected element %s, got %s", path, xmlName(expected.name), xmlName(actual.name))}
+	}
+
+	var mismatches []string
+	names := make([]xml.Name, 0, len(expected.attrs)+len(actual.attrs))
+	for name := range expected.attrs {
+		names = append(names, name)
+	}
+	for name := range actual.attrs {
+		if _, ok := expected.attrs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return xmlName(names[i]) < xmlName(names[j])
+	})
+	for _, name := range names {
+		expectedValue, expectedOK := expected.attrs[name]
+		actualValue, actualOK := actual.attrs[name]
+		switch {
+		case !actualOK:
+			mismatches = append(mismatches, fmt.Sprintf("%s/@%s: missing", path, xmlName(name)))
+		case !expectedOK:
+			mismatches = append(mismatches, fmt.Sprintf("%s/@%s: unexpected %q", path, xmlName(name), actualValue))
+		case expectedValue != actualValue:
+			mismatches = append(mismatches, fmt.Sprintf("%s/@%s: expected %q, got %q", path, xmlName(name), expectedValue, actualValue))
+		}
+	}
+
+	if expected.text != actual.text {
+		mismatches = append(mismatches, fmt.Sprintf("%s: expected text %q, got %q", path, expected.text, actual.text))
+	}
+
+	if len(expected.children) != len(actual.children) {
+		mismatches = append(mismatches, fmt.Sprintf("%s: expected %d child elements, got %d", path, len(expected.children), len(actual.children)))
+	}
+	for i := 0; i < len(expected.children) && i < len(actual.children); i++ {
+		mismatches = append(mismatches, xmlMismatches(expected.children[i], actual.children[i], xmlChildPath(path, expected, i))...)
+	}
+	return mismatches
+}
+
+func xmlChildPath(path string, parent *xmlNode, i int) string {
+	child := parent.children[i]
+	index, count := 0, 0
+	for j, sibling := range parent.children {
+		if sibling.name == child.name {
+			count++
+			if j <= i {
+				index++
+			}
+		}
+	}
+	if count > 1 {
+		return fmt.Sprintf("%s/%s[%d]", path, child.name.Local, index)
+	}
+	return path + "/" + child.name.Local
+}
+
+type xmlPathExpectation struct {
+	path  string
+	value string
+}
+
+func (e xmlPathExpectation) String() string {
+	return fmt.Sprintf("%s == %q", e.path, e.value)
+}
+
+func (e xmlPathExpectation) mismatch(root *xmlNode) string {
+	value, err := lookupXMLPath(root, e.path)
+	if err != nil {
+		return fmt.Sprintf("%s: %s", e.path, err)
+	}
+	if value != e.value {
+		return fmt.Sprintf("%s: expected %q, got %q", e.path, e.value, value)
+	}
+	return ""
+}
+
+func lookupXMLPath(root *xmlNode, path string) (string, error) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return "", fmt.Errorf("empty path")
+	}
+
+	current := &xmlNode{children: []*xmlNode{root}}
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "@") {
+			for name, value := range current.attrs {
+				if name.Local == segment[1:] {
+					return value, nil
+				}
+			}
+			return "", fmt.Errorf("missing")
+		}
+
+		local, index := segment, 1
+		if i := strings.Index(segment, "["); i >= 0 && strings.HasSuffix(segment, "]") {
+			n, err := strconv.Atoi(segment[i+1 : len(segment)-1])
+			if err != nil || n < 1 {
+				return "", fmt.Errorf("invalid index in %q", segment)
+			}
+			local, index = segment[:i], n
+		}
+
+		var next *xmlNode
+		for _, child := range current.children {
+			if child.name.Local == local {
+				if index--; index == 0 {
+					next = child
+					break
+				}
+			}
+		}
+		if next == nil {
+			return "", fmt.Errorf("missing")
+		}
+		current = next
+	}
+	return current.text, nil
+}
+
+func xmlBodyMismatches(body []byte, req *Request) []string {
+	if len(req.expectedXML) == 0 && len(req.expectedXMLPaths) == 0 {
+		return nil
+	}
+
+	actual, err := parseXML(body)
+	if err != nil {
+		return []string{fmt.Sprintf("body is not valid XML: %s", err)}
+	}
+
+	var mismatches []string
+	if len(req.expectedXML) > 0 {
+		expected, err := parseXML(req.expectedXML)
+		if err != nil {
+			return []string{fmt.Sprintf("invalid expected XML: %s", err)}
+		}
+		mismatches = append(mismatches, xmlMismatches(expected, actual, "/"+expected.name.Local)...)
+	}
+	for _, expectation := range req.expectedXMLPaths {
+		if mismatch := expectation.mismatch(actual); mismatch != "" {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches
+}
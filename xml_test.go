@@ -0,0 +1,81 @@
+package httpmock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedSOAP = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">
+	<soap:Body>
+		<u:GetUser version="2" locale="fr">
+			<u:Id>42</u:Id>
+			<u:Field>name</u:Field>
+			<u:Field>email</u:Field>
+		</u:GetUser>
+	</soap:Body>
+</soap:Envelope>`
+
+func Test_xmlMismatches(t *testing.T) {
+	tests := []struct {
+		name               string
+		actual             string
+		expectedMismatches []string
+	}{
+		{
+			name:   "other prefixes, attribute order and whitespace",
+			actual: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><GetUser xmlns="urn:users" locale="fr" version="2"><Id> 42 </Id><Field>name</Field><Field>email</Field></GetUser></s:Body></s:Envelope>`,
+		},
+		{
+			name:   "differences",
+			actual: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><GetUser xmlns="urn:users" version="3" debug="true"><Id>43</Id><Field>name</Field><Field>phone</Field></GetUser></s:Body></s:Envelope>`,
+			expectedMismatches: []string{
+				`/Envelope/Body/GetUser/@debug: unexpected "true"`,
+				"/Envelope/Body/GetUser/@locale: missing",
+				`/Envelope/Body/GetUser/@version: expected "2", got "3"`,
+				`/Envelope/Body/GetUser/Id: expected text "42", got "43"`,
+				`/Envelope/Body/GetUser/Field[2]: expected text "email", got "phone"`,
+			},
+		},
+		{
+			name:   "namespace and children",
+			actual: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><GetUser xmlns="urn:accounts"/></s:Body><s:Footer/></s:Envelope>`,
+			expectedMismatches: []string{
+				"/Envelope: expected 1 child elements, got 2",
+				"/Envelope/Body/GetUser: expected element {urn:users}GetUser, got {urn:accounts}GetUser",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := parseXML([]byte(expectedSOAP))
+			assert.NoError(t, err)
+			actual, err := parseXML([]byte(tt.actual))
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedMismatches, xmlMismatches(expected, actual, "/Envelope"))
+		})
+	}
+}
+
+func Test_lookupXMLPath(t *testing.T) {
+	root, err := parseXML([]byte(expectedSOAP))
+	assert.NoError(t, err)
+
+	value, err := lookupXMLPath(root, "/Envelope/Body/GetUser/Id")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	value, err = lookupXMLPath(root, "/Envelope/Body/GetUser/Field[2]")
+	assert.NoError(t, err)
+	assert.Equal(t, "email", value)
+
+	value, err = lookupXMLPath(root, "/Envelope/Body/GetUser/@locale")
+	assert.NoError(t, err)
+	assert.Equal(t, "fr", value)
+
+	_, err = lookupXMLPath(root, "/Envelope/Body/GetUser/Field[3]")
+	assert.EqualError(t, err, "missing")
+
+	assert.Equal(t, `/Envelope/Body/GetUser/Id: expected "41", got "42"`, xmlPathExpectation{path: "/Envelope/Body/GetUser/Id", value: "41"}.mismatch(root))
+}